@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+)
+
+// datafile is a YAML datafile edited as a node tree so comments and ordering
+// survive. Encrypted values are mappings like
+// `{value: <data--iv--authtag>, decrypt_with: <secret key config>}`.
+type datafile struct {
+	document yaml.Node
+}
+
+func readDatafile(file string) (*datafile, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	datafile := &datafile{}
+	if err := yaml.Unmarshal(contents, &datafile.document); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+
+	if len(datafile.document.Content) == 0 || datafile.document.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is not a YAML mapping", file)
+	}
+
+	return datafile, nil
+}
+
+// editDatafile applies edit to file and writes it back if edit succeeds.
+func editDatafile(file string, edit func(*datafile) error) error {
+	datafile, err := readDatafile(file)
+	if err != nil {
+		return err
+	}
+
+	if err := edit(datafile); err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&datafile.document); err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, buffer.Bytes(), info.Mode().Perm())
+}
+
+func (d *datafile) encrypt(path string, key string, decryptWith string) error {
+	node, err := d.find(path)
+	if err != nil {
+		return err
+	}
+
+	if isEncrypted(node) {
+		return fmt.Errorf("%s is already encrypted", path)
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%s is not a single value", path)
+	}
+
+	encrypted, err := prefab.EncryptValue(key, node.Value)
+	if err != nil {
+		return err
+	}
+
+	*node = yaml.Node{
+		Kind:        yaml.MappingNode,
+		Style:       yaml.FlowStyle,
+		LineComment: node.LineComment,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "value"},
+			{Kind: yaml.ScalarNode, Value: encrypted, Style: yaml.DoubleQuotedStyle},
+			{Kind: yaml.ScalarNode, Value: "decrypt_with"},
+			{Kind: yaml.ScalarNode, Value: decryptWith, Style: yaml.DoubleQuotedStyle},
+		},
+	}
+
+	return nil
+}
+
+func (d *datafile) decrypt(path string, key string) (string, error) {
+	node, err := d.find(path)
+	if err != nil {
+		return "", err
+	}
+
+	if !isEncrypted(node) {
+		return "", fmt.Errorf("%s is not encrypted", path)
+	}
+
+	return prefab.DecryptValue(key, mappingValue(node, "value").Value)
+}
+
+// reencrypt re-encrypts every encrypted value from key to newKey, returning
+// how many there were. Nothing is changed if any value can't be decrypted.
+func (d *datafile) reencrypt(key string, newKey string) (int, error) {
+	var encryptedNodes []*yaml.Node
+
+	walk(d.document.Content[0], func(node *yaml.Node) {
+		if isEncrypted(node) {
+			encryptedNodes = append(encryptedNodes, mappingValue(node, "value"))
+		}
+	})
+
+	reencrypted := make([]string, len(encryptedNodes))
+
+	for i, node := range encryptedNodes {
+		plaintext, err := prefab.DecryptValue(key, node.Value)
+		if err != nil {
+			return 0, fmt.Errorf("decrypting value on line %d: %w", node.Line, err)
+		}
+
+		if reencrypted[i], err = prefab.EncryptValue(newKey, plaintext); err != nil {
+			return 0, err
+		}
+	}
+
+	for i, node := range encryptedNodes {
+		node.Value = reencrypted[i]
+	}
+
+	return len(encryptedNodes), nil
+}
+
+// find returns the node for a dotted config key. As in the datafile parser,
+// keys may be nested mappings, may contain dots themselves, and a mapping's
+// own value is under "_".
+func (d *datafile) find(path string) (*yaml.Node, error) {
+	node := findIn(d.document.Content[0], strings.Split(path, "."))
+	if node == nil {
+		return nil, fmt.Errorf("%s is not in the datafile", path)
+	}
+
+	return node, nil
+}
+
+func findIn(mapping *yaml.Node, parts []string) *yaml.Node {
+	if mapping.Kind != yaml.MappingNode || isEncrypted(mapping) {
+		return nil
+	}
+
+	for i := len(parts); i > 0; i-- {
+		value := mappingValue(mapping, strings.Join(parts[:i], "."))
+		if value == nil {
+			continue
+		}
+
+		if i == len(parts) {
+			if underscore := mappingValue(value, "_"); value.Kind == yaml.MappingNode && !isEncrypted(value) && underscore != nil {
+				return underscore
+			}
+
+			return value
+		}
+
+		if found := findIn(value, parts[i:]); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func isEncrypted(node *yaml.Node) bool {
+	value := mappingValue(node, "value")
+
+	return mappingValue(node, "decrypt_with") != nil && value != nil && value.Kind == yaml.ScalarNode
+}
+
+func walk(node *yaml.Node, visit func(*yaml.Node)) {
+	visit(node)
+
+	for _, child := range node.Content {
+		walk(child, visit)
+	}
+}
@@ -0,0 +1,177 @@
+// Command prefab-secrets encrypts and decrypts confidential config values in
+// the format Prefab uses, either on their own or in place in local YAML
+// datafiles.
+//
+// Usage:
+//
+//	prefab-secrets generate-key
+//	prefab-secrets encrypt [-key KEY] VALUE
+//	prefab-secrets encrypt [-key KEY] -file FILE -path KEY.PATH -decrypt-with SECRET.KEY.CONFIG
+//	prefab-secrets decrypt [-key KEY] VALUE
+//	prefab-secrets decrypt [-key KEY] -file FILE -path KEY.PATH
+//	prefab-secrets reencrypt [-key KEY] -new-key NEW_KEY -file FILE
+//
+// The key defaults to the PREFAB_SECRET_KEY environment variable so it needn't
+// appear in shell history. VALUE may be "-" to read it from stdin.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+)
+
+const secretKeyEnvVar = "PREFAB_SECRET_KEY"
+
+var errUsage = errors.New("usage: prefab-secrets generate-key|encrypt|decrypt|reencrypt [flags] [VALUE]")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Getenv); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, getenv func(string) string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	command, args := args[0], args[1:]
+
+	switch command {
+	case "generate-key", "encrypt", "decrypt", "reencrypt":
+	default:
+		return errUsage
+	}
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	key := flags.String("key", getenv(secretKeyEnvVar), "hex-encoded secret key (default $"+secretKeyEnvVar+")")
+	newKey := flags.String("new-key", "", "hex-encoded secret key to re-encrypt with")
+	file := flags.String("file", "", "YAML datafile to edit")
+	path := flags.String("path", "", "dotted key of the value in -file")
+	decryptWith := flags.String("decrypt-with", "", "config holding the secret key, recorded with values encrypted in -file")
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	if command != "generate-key" && *key == "" {
+		return fmt.Errorf("a secret key is required: pass -key or set %s", secretKeyEnvVar)
+	}
+
+	switch {
+	case command == "generate-key":
+		secretKey, err := prefab.GenerateSecretKey()
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(stdout, secretKey)
+
+		return err
+	case command == "encrypt" && *file != "":
+		if *path == "" || *decryptWith == "" {
+			return errors.New("encrypting in a file needs -path and -decrypt-with")
+		}
+
+		return editDatafile(*file, func(datafile *datafile) error {
+			return datafile.encrypt(*path, *key, *decryptWith)
+		})
+	case command == "encrypt":
+		value, err := valueArg(flags.Args(), stdin)
+		if err != nil {
+			return err
+		}
+
+		encrypted, err := prefab.EncryptValue(*key, value)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(stdout, encrypted)
+
+		return err
+	case command == "decrypt":
+		var (
+			value string
+			err   error
+		)
+
+		if *file != "" {
+			value, err = decryptFromDatafile(*file, *path, *key)
+		} else {
+			value, err = valueArg(flags.Args(), stdin)
+			if err == nil {
+				value, err = prefab.DecryptValue(*key, value)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(stdout, value)
+
+		return err
+	case command == "reencrypt":
+		if *newKey == "" || *file == "" {
+			return errors.New("reencrypt needs -new-key and -file")
+		}
+
+		count := 0
+
+		err := editDatafile(*file, func(datafile *datafile) error {
+			var err error
+			count, err = datafile.reencrypt(*key, *newKey)
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(stdout, "re-encrypted %d values\n", count)
+
+		return err
+	default:
+		return errUsage
+	}
+}
+
+func valueArg(args []string, stdin io.Reader) (string, error) {
+	if len(args) != 1 {
+		return "", errUsage
+	}
+
+	if args[0] != "-" {
+		return args[0], nil
+	}
+
+	value, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(value), "\r\n"), nil
+}
+
+func decryptFromDatafile(file string, path string, key string) (string, error) {
+	if path == "" {
+		return "", errors.New("decrypting from a file needs -path")
+	}
+
+	datafile, err := readDatafile(file)
+	if err != nil {
+		return "", err
+	}
+
+	return datafile.decrypt(path, key)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+)
+
+func runCommand(t *testing.T, env map[string]string, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout bytes.Buffer
+
+	err := run(args, strings.NewReader(stdin), &stdout, func(key string) string { return env[key] })
+
+	return strings.TrimSpace(stdout.String()), err
+}
+
+func TestEncryptAndDecryptValues(t *testing.T) {
+	key, err := runCommand(t, nil, "", "generate-key")
+	require.NoError(t, err)
+	assert.Len(t, key, 64)
+
+	env := map[string]string{secretKeyEnvVar: key}
+
+	encrypted, err := runCommand(t, env, "from stdin\n", "encrypt", "-")
+	require.NoError(t, err)
+
+	decrypted, err := prefab.DecryptValue(key, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "from stdin", decrypted)
+
+	decrypted, err = runCommand(t, nil, "", "decrypt", "-key", key, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "from stdin", decrypted)
+
+	_, err = runCommand(t, nil, "", "encrypt", "value")
+	require.ErrorContains(t, err, "a secret key is required")
+
+	_, err = runCommand(t, nil, "", "unknown")
+	require.ErrorIs(t, err, errUsage)
+}
+
+func TestEditDatafile(t *testing.T) {
+	oldKey, err := prefab.GenerateSecretKey()
+	require.NoError(t, err)
+
+	newKey, err := prefab.GenerateSecretKey()
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), ".prefab.default.config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`# local overrides
+db:
+  host: localhost
+  password: hunter2 # change me
+api.token:
+  _: abc123
+  timeout: 5
+`), 0o600))
+
+	env := map[string]string{secretKeyEnvVar: oldKey}
+
+	for _, path := range []string{"db.password", "api.token"} {
+		_, err = runCommand(t, env, "", "encrypt", "-file", file, "-path", path, "-decrypt-with", "secret.key")
+		require.NoError(t, err, path)
+	}
+
+	_, err = runCommand(t, env, "", "encrypt", "-file", file, "-path", "db.password", "-decrypt-with", "secret.key")
+	require.ErrorContains(t, err, "already encrypted")
+
+	_, err = runCommand(t, env, "", "encrypt", "-file", file, "-path", "db.missing", "-decrypt-with", "secret.key")
+	require.ErrorContains(t, err, "not in the datafile")
+
+	contents, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "hunter2")
+	assert.Contains(t, string(contents), "# local overrides")
+	assert.Contains(t, string(contents), "# change me")
+	assert.Contains(t, string(contents), "host: localhost")
+
+	output, err := runCommand(t, env, "", "reencrypt", "-new-key", newKey, "-file", file)
+	require.NoError(t, err)
+	assert.Equal(t, "re-encrypted 2 values", output)
+
+	_, err = runCommand(t, env, "", "decrypt", "-file", file, "-path", "db.password")
+	require.Error(t, err, "the old key no longer works")
+
+	password, err := runCommand(t, nil, "", "decrypt", "-key", newKey, "-file", file, "-path", "db.password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", password)
+
+	token, err := runCommand(t, nil, "", "decrypt", "-key", newKey, "-file", file, "-path", "api.token")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", token)
+
+	_, err = runCommand(t, nil, "", "decrypt", "-key", newKey, "-file", file, "-path", "db.host")
+	require.ErrorContains(t, err, "not encrypted")
+}
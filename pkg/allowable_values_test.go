@@ -0,0 +1,119 @@
+package prefab_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestAllowableValues(t *testing.T) {
+	colorConfig := func(key string, id int64, value *prefabProto.ConfigValue) *prefabProto.Config {
+		config := testConfig(key, id, prefabProto.ConfigType_CONFIG, always(value))
+		config.ValueType = prefabProto.Config_STRING
+		config.AllowableValues = []*prefabProto.ConfigValue{
+			testutils.CreateConfigValueAndAssertOk(t, "red"),
+			testutils.CreateConfigValueAndAssertOk(t, "blue"),
+		}
+
+		return config
+	}
+
+	providedColor := &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Provided{Provided: &prefabProto.Provided{
+		Source: prefabProto.ProvidedSource_ENV_VAR.Enum(),
+		Lookup: internal.StringPtr("COLOR"),
+	}}}
+
+	configs := []*prefabProto.Config{
+		colorConfig("color", 1, testutils.CreateConfigValueAndAssertOk(t, "red")),
+		colorConfig("legacy.color", 2, testutils.CreateConfigValueAndAssertOk(t, "green")),
+		colorConfig("env.color", 3, providedColor),
+		colorConfig("overridden.color", 4, testutils.CreateConfigValueAndAssertOk(t, "blue")),
+	}
+
+	overrides := filepath.Join(t.TempDir(), "overrides.yaml")
+	require.NoError(t, os.WriteFile(overrides, []byte("overridden.color: purple\n"), 0o600))
+
+	newClient := func(opts ...prefab.Option) (*prefab.Client, chan<- *prefabProto.Configs) {
+		return newTestClientWithOptions(t, configs, append([]prefab.Option{
+			prefab.WithSources([]string{"datafile://" + overrides}, false),
+			prefab.WithEnvLookup(mapEnvLookup{"COLOR": "green"}),
+		}, opts...)...)
+	}
+
+	t.Run("rejecting disallowed values", func(t *testing.T) {
+		client, updates := newClient()
+
+		value, ok, err := client.GetStringValue("color", prefab.ContextSet{})
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "red", value)
+
+		for _, key := range []string{"env.color", "overridden.color"} {
+			_, ok, err = client.GetStringValue(key, prefab.ContextSet{})
+			require.ErrorIs(t, err, prefab.ErrValueNotAllowed, key)
+			assert.False(t, ok, key)
+		}
+
+		_, _, err = client.GetStringValue("legacy.color", prefab.ContextSet{})
+		require.ErrorIs(t, err, prefab.ErrConfigDoesNotExist, "configs with disallowed values are ignored when loaded")
+
+		enumValue, allowed, ok, err := prefab.GetEnum[string](client, "color", prefab.ContextSet{})
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "red", enumValue)
+		assert.Equal(t, []string{"red", "blue"}, allowed)
+
+		events, stop := client.Watch("other")
+		defer stop()
+
+		sendUpdate(t, updates, &prefabProto.Configs{Configs: []*prefabProto.Config{
+			colorConfig("color", 5, testutils.CreateConfigValueAndAssertOk(t, "green")),
+			stringConfig(t, "other", 6, "x"),
+		}})
+
+		select {
+		case <-events:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the update")
+		}
+
+		value, _, err = client.GetStringValue("color", prefab.ContextSet{})
+		require.NoError(t, err)
+		assert.Equal(t, "red", value, "the disallowed update is ignored")
+	})
+
+	t.Run("falling back to the first allowed value", func(t *testing.T) {
+		client, _ := newClient(prefab.WithOnDisallowedValue(prefab.UseFirstAllowedValue))
+
+		for _, key := range []string{"env.color", "overridden.color"} {
+			value, ok, err := client.GetStringValue(key, prefab.ContextSet{})
+			require.NoError(t, err, key)
+			assert.True(t, ok, key)
+			assert.Equal(t, "red", value, key)
+
+			details, _, err := client.GetStringDetails(key, prefab.ContextSet{})
+			require.NoError(t, err, key)
+			assert.Equal(t, prefab.ReasonDisallowedValue, details.Reason, key)
+		}
+
+		details, _, err := client.GetStringDetails("color", prefab.ContextSet{})
+		require.NoError(t, err)
+		assert.Equal(t, prefab.ReasonDefault, details.Reason, "allowed values keep their reason")
+	})
+
+	t.Run("configs passed to WithConfigs are checked", func(t *testing.T) {
+		_, err := prefab.NewClient(
+			prefab.WithConfigs(map[string]interface{}{"color": colorConfig("color", 1, testutils.CreateConfigValueAndAssertOk(t, "green"))}),
+			prefab.WithAllTelemetryDisabled())
+		require.ErrorIs(t, err, prefab.ErrValueNotAllowed)
+	})
+}
@@ -2,15 +2,20 @@
 package prefab
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/connection"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/contexts"
 	optionsPkg "github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/ratelimit"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/secrets"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/stores"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/telemetry"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/utils"
@@ -25,6 +30,9 @@ type ConfigMatch = internal.ConfigMatch
 // ContextSet is a set of NamedContext
 type ContextSet = contexts.ContextSet
 
+// TelemetryStats counts telemetry the client dropped because its queue was full.
+type TelemetryStats = telemetry.Stats
+
 // NamedContext is a named context. It is used to provide context to the client about the current user/machine/etc.
 type NamedContext = contexts.NamedContext
 
@@ -38,10 +46,79 @@ const (
 	ReturnError optionsPkg.OnInitializationFailure = optionsPkg.ReturnError
 	// ReturnNilMatch will continue (generally returning a zero value, ok=false result) if initialization times out
 	ReturnNilMatch optionsPkg.OnInitializationFailure = optionsPkg.ReturnNilMatch
+
+	// RejectDisallowedValue returns an error when a config resolves to a value outside its allowable values
+	RejectDisallowedValue optionsPkg.OnDisallowedValue = optionsPkg.RejectDisallowedValue
+	// UseFirstAllowedValue replaces a value outside a config's allowable values with the first allowable value
+	UseFirstAllowedValue optionsPkg.OnDisallowedValue = optionsPkg.UseFirstAllowedValue
 )
 
 var ContextTelemetryMode = optionsPkg.ContextTelemetryModes
 
+var (
+	// ErrClientClosed is returned by getters called after Close or Shutdown.
+	ErrClientClosed = errors.New("client closed")
+	// ErrInitializationTimeout is returned by getters when the client isn't ready within InitializationTimeoutSeconds (and OnInitializationFailure is ReturnError).
+	ErrInitializationTimeout = errors.New("initialization timeout")
+	// ErrConfigDoesNotExist is returned when the requested key isn't in any config source.
+	ErrConfigDoesNotExist = internal.ErrConfigDoesNotExist
+	// ErrProvidedValueNotFound is returned when a config's provided value (e.g. an environment variable) can't be found by the ProvidedResolver.
+	ErrProvidedValueNotFound = internal.ErrProvidedValueNotFound
+	// ErrDecryptionCycle is returned when a config's decrypt_with key is, directly or through other keys, decrypted with the config itself.
+	ErrDecryptionCycle = internal.ErrDecryptionCycle
+	// ErrSchemaValidation is matched by the *SchemaValidationError returned when a JSON value doesn't match its schema.
+	ErrSchemaValidation = internal.ErrSchemaValidation
+	// ErrValueNotAllowed is matched by the *ValueNotAllowedError returned when a value isn't in its config's allowable values.
+	ErrValueNotAllowed = internal.ErrValueNotAllowed
+	// ErrSecretKeyNotFound should be returned by a SecretKeyProvider that doesn't know a key, so the decrypt_with config is used instead.
+	ErrSecretKeyNotFound = secrets.ErrSecretKeyNotFound
+	// ErrBuiltInOperator is returned by NewClient when WithCustomOperator is given a built-in operator.
+	ErrBuiltInOperator = internal.ErrBuiltInOperator
+)
+
+// SchemaValidationError reports a JSON config value that doesn't match the
+// JSON schema named by the config's SchemaKey.
+type SchemaValidationError = internal.SchemaValidationError
+
+// ProvidedResolver looks up the values of configs provided outside Prefab
+// (e.g. by an environment variable). See WithProvidedResolver.
+type ProvidedResolver = utils.ProvidedResolver
+
+// EnvProvidedResolver reads provided values from environment variables. It is
+// the default ProvidedResolver.
+type EnvProvidedResolver = utils.EnvProvidedResolver
+
+// FileProvidedResolver reads provided values from files, such as secrets
+// mounted under /run/secrets.
+type FileProvidedResolver = utils.FileProvidedResolver
+
+// MapProvidedResolver reads provided values from a map.
+type MapProvidedResolver = utils.MapProvidedResolver
+
+// ProvidedResolverChain tries several ProvidedResolvers in order.
+type ProvidedResolverChain = utils.ProvidedResolverChain
+
+// SecretKeyProvider supplies the keys for decrypting encrypted config values.
+// See WithSecretKeyProvider.
+type SecretKeyProvider = secrets.SecretKeyProvider
+
+// SecretKeyProviderFunc adapts a function to a SecretKeyProvider.
+type SecretKeyProviderFunc = secrets.SecretKeyProviderFunc
+
+// EnvSecretKeyProvider reads secret keys from environment variables.
+type EnvSecretKeyProvider = secrets.EnvSecretKeyProvider
+
+// FileSecretKeyProvider reads secret keys from files.
+type FileSecretKeyProvider = secrets.FileSecretKeyProvider
+
+// CustomOperator decides whether a criterion with a custom operator matches.
+// See WithCustomOperator.
+type CustomOperator = internal.CustomOperator
+
+// ValueNotAllowedError reports a config value that isn't one of the config's
+// allowable values.
+type ValueNotAllowedError = internal.ValueNotAllowedError
+
 // ClientInterface is the interface for the Prefab client
 type ClientInterface interface {
 	GetIntValue(key string, contextSet ContextSet) (int64, bool, error)
@@ -78,18 +155,23 @@ type Client struct {
 	options                         *optionsPkg.Options
 	configStore                     internal.ConfigStoreGetter
 	configResolver                  *internal.ConfigResolver
+	changeNotifier                  *internal.ConfigChangeNotifier
+	connection                      *connection.Tracker
+	limiter                         *ratelimit.Limiter
 	initializationComplete          chan struct{}
 	closeInitializationCompleteOnce sync.Once
-	telemetry                       telemetry.Submitter
+	telemetry                       *telemetry.Submitter
 	instanceHash                    string
+	closed                          chan struct{}
+	closeOnce                       sync.Once
+	shutdownComplete                chan struct{}
+	shutdownErr                     error
 }
 
 // NewClient creates a new Prefab client. It takes options as arguments (e.g. WithAPIKey)
 func NewClient(opts ...Option) (*Client, error) {
 	options := optionsPkg.GetDefaultOptions()
 
-	var client Client
-
 	for _, opt := range opts {
 		if err := opt(&options); err != nil {
 			return nil, err
@@ -98,6 +180,36 @@ func NewClient(opts ...Option) (*Client, error) {
 
 	slog.Debug("Initializing client", "options", options)
 
+	if (len(options.Sources) > 1 || options.Sources[0].Raw != optionsPkg.MemoryStoreKey) && len(options.Configs) > 0 {
+		return nil, errors.New("cannot use WithConfigs with other sources")
+	}
+
+	customOperators := internal.NewOperatorRegistry()
+
+	for operator, fn := range options.CustomOperators {
+		if err := customOperators.Register(operator, fn); err != nil {
+			return nil, err
+		}
+	}
+
+	// The client is allocated before the stores are built because an API
+	// store can finish loading (and close initializationComplete) right away.
+	client := &Client{
+		options:                &options,
+		changeNotifier:         internal.NewConfigChangeNotifier(),
+		connection:             connection.NewTracker(),
+		limiter:                ratelimit.New(),
+		initializationComplete: make(chan struct{}),
+		telemetry:              telemetry.NewTelemetrySubmitter(options),
+		instanceHash:           options.InstanceHash,
+		closed:                 make(chan struct{}),
+		shutdownComplete:       make(chan struct{}),
+	}
+
+	if options.OnStateChange != nil {
+		client.connection.Subscribe(options.OnStateChange)
+	}
+
 	var configStores []internal.ConfigStoreGetter
 
 	apiSourceFinishedLoading := func() {
@@ -109,8 +221,10 @@ func NewClient(opts ...Option) (*Client, error) {
 	anyAsync := false
 
 	for _, source := range options.Sources {
-		configStore, asyncInit, err := stores.BuildConfigStore(options, source, apiSourceFinishedLoading)
+		configStore, asyncInit, err := stores.BuildConfigStore(options, source, apiSourceFinishedLoading, client.changeNotifier, client.connection)
 		if err != nil {
+			closeConfigStores(configStores)
+
 			return nil, err
 		}
 
@@ -121,36 +235,132 @@ func NewClient(opts ...Option) (*Client, error) {
 		configStores = append(configStores, configStore)
 	}
 
-	if (len(options.Sources) > 1 || options.Sources[0].Raw != optionsPkg.MemoryStoreKey) && len(options.Configs) > 0 {
-		return nil, errors.New("cannot use WithConfigs with other sources")
-	}
-
-	configStore := stores.BuildCompositeConfigStore(configStores...)
+	client.configStore = stores.BuildCompositeConfigStore(configStores...)
+	client.configResolver = internal.NewConfigResolver(client.configStore, options.CustomEnvLookup)
+	client.configResolver.UseFirstAllowedValue = options.OnDisallowedValue == optionsPkg.UseFirstAllowedValue
+	client.configResolver.ProvidedResolver = options.ProvidedResolver
+	client.configResolver.SecretKeyProvider = options.SecretKeyProvider
 
-	configResolver := internal.NewConfigResolver(configStore, options.CustomEnvLookup)
+	decryptionCache := client.configResolver.DecryptionCache
+	client.changeNotifier.Subscribe(func(event ChangeEvent) {
+		if event.Type == ChangeTypeUpdated || event.Type == ChangeTypeDeleted {
+			decryptionCache.Evict(event.Key)
+		}
+	})
 
-	client = Client{
-		options:                &options,
-		configStore:            configStore,
-		configResolver:         configResolver,
-		initializationComplete: make(chan struct{}),
-		telemetry:              *telemetry.NewTelemetrySubmitter(options),
-		instanceHash:           options.InstanceHash,
+	if ruleEvaluator, ok := client.configResolver.RuleEvaluator.(*internal.ConfigRuleEvaluator); ok {
+		ruleEvaluator.OnUnknownOperator = options.OnUnknownOperator
+		ruleEvaluator.CustomOperators = customOperators
 	}
 
 	if !anyAsync {
 		client.closeInitializationCompleteOnce.Do(func() {
 			close(client.initializationComplete)
 		})
+		client.connection.SetState(connection.Loaded, nil)
 	}
 
-	client.boundClient = &ContextBoundClient{client: &client, context: options.GlobalContext}
+	client.boundClient = &ContextBoundClient{client: client, context: options.GlobalContext}
 
 	if options.TelemetryEnabled() {
 		client.telemetry.StartPeriodicSubmission(options.TelemetrySyncInterval)
 	}
 
-	return &client, nil
+	return client, nil
+}
+
+// closeConfigStores stops the background work of the stores built before
+// NewClient failed.
+func closeConfigStores(configStores []internal.ConfigStoreGetter) {
+	for _, configStore := range configStores {
+		if closer, ok := configStore.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
+}
+
+// closeTimeout is how long Close waits for shutdown, including the final
+// telemetry submission.
+const closeTimeout = 10 * time.Second
+
+// Close stops all background work (the config fetch and its retries, the SSE
+// stream and telemetry) and sends any pending telemetry. It is equivalent to
+// Shutdown with a context that times out after 10 seconds.
+func (c *Client) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+
+	return c.Shutdown(ctx)
+}
+
+// Shutdown stops all background work started by NewClient: the initial config
+// fetch and its retries, the SSE stream, and the telemetry goroutines. Queued
+// telemetry is drained and submitted one last time.
+//
+// If ctx is done before shutdown completes, Shutdown returns ctx.Err() and the
+// final telemetry submission is abandoned. Once Shutdown has been called,
+// getters return ErrClientClosed. It is safe to call Shutdown more than once.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+
+		go func() {
+			defer close(c.shutdownComplete)
+
+			var errs []error
+
+			if closer, ok := c.configStore.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			if err := c.telemetry.Shutdown(ctx); err != nil {
+				errs = append(errs, err)
+			}
+
+			c.shutdownErr = errors.Join(errs...)
+		}()
+	})
+
+	select {
+	case <-c.shutdownComplete:
+		return c.shutdownErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitForReady blocks until the client has finished loading its config sources,
+// the client is closed, or ctx is done. Unlike the getters it does not give up
+// after InitializationTimeoutSeconds; use a context deadline to bound the wait.
+//
+// It returns nil once the client is ready, ErrClientClosed if the client was
+// closed first, or ctx.Err().
+func (c *Client) WaitForReady(ctx context.Context) error {
+	select {
+	case <-c.initializationComplete:
+		return nil
+	default:
+	}
+
+	select {
+	case <-c.initializationComplete:
+		return nil
+	case <-c.closed:
+		return ErrClientClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
 }
 
 // GetIntValue returns an int value for a given key and context
@@ -158,81 +368,161 @@ func (c *Client) GetIntValue(key string, contextSet ContextSet) (value int64, ok
 	return c.boundClient.GetIntValue(key, contextSet)
 }
 
+// GetIntValueCtx is like GetIntValue but stops waiting for initialization when ctx is done.
+func (c *Client) GetIntValueCtx(ctx context.Context, key string, contextSet ContextSet) (value int64, ok bool, err error) {
+	return c.boundClient.GetIntValueCtx(ctx, key, contextSet)
+}
+
 // GetBoolValue returns a bool value for a given key and context
 func (c *Client) GetBoolValue(key string, contextSet ContextSet) (value bool, ok bool, err error) {
 	return c.boundClient.GetBoolValue(key, contextSet)
 }
 
+// GetBoolValueCtx is like GetBoolValue but stops waiting for initialization when ctx is done.
+func (c *Client) GetBoolValueCtx(ctx context.Context, key string, contextSet ContextSet) (value bool, ok bool, err error) {
+	return c.boundClient.GetBoolValueCtx(ctx, key, contextSet)
+}
+
 // GetStringValue returns a string value for a given key and context
 func (c *Client) GetStringValue(key string, contextSet ContextSet) (value string, ok bool, err error) {
 	return c.boundClient.GetStringValue(key, contextSet)
 }
 
+// GetStringValueCtx is like GetStringValue but stops waiting for initialization when ctx is done.
+func (c *Client) GetStringValueCtx(ctx context.Context, key string, contextSet ContextSet) (value string, ok bool, err error) {
+	return c.boundClient.GetStringValueCtx(ctx, key, contextSet)
+}
+
 // GetFloatValue returns a float value for a given key and context
 func (c *Client) GetFloatValue(key string, contextSet ContextSet) (value float64, ok bool, err error) {
 	return c.boundClient.GetFloatValue(key, contextSet)
 }
 
+// GetFloatValueCtx is like GetFloatValue but stops waiting for initialization when ctx is done.
+func (c *Client) GetFloatValueCtx(ctx context.Context, key string, contextSet ContextSet) (value float64, ok bool, err error) {
+	return c.boundClient.GetFloatValueCtx(ctx, key, contextSet)
+}
+
 // GetStringSliceValue returns a string slice value for a given key and context
 func (c *Client) GetStringSliceValue(key string, contextSet ContextSet) (value []string, ok bool, err error) {
 	return c.boundClient.GetStringSliceValue(key, contextSet)
 }
 
+// GetStringSliceValueCtx is like GetStringSliceValue but stops waiting for initialization when ctx is done.
+func (c *Client) GetStringSliceValueCtx(ctx context.Context, key string, contextSet ContextSet) (value []string, ok bool, err error) {
+	return c.boundClient.GetStringSliceValueCtx(ctx, key, contextSet)
+}
+
 // GetDurationValue returns a duration value for a given key and context
 func (c *Client) GetDurationValue(key string, contextSet ContextSet) (value time.Duration, ok bool, err error) {
 	return c.boundClient.GetDurationValue(key, contextSet)
 }
 
+// GetDurationValueCtx is like GetDurationValue but stops waiting for initialization when ctx is done.
+func (c *Client) GetDurationValueCtx(ctx context.Context, key string, contextSet ContextSet) (value time.Duration, ok bool, err error) {
+	return c.boundClient.GetDurationValueCtx(ctx, key, contextSet)
+}
+
 // GetJSONValue returns a JSON value for a given key and context
 func (c *Client) GetJSONValue(key string, contextSet ContextSet) (value interface{}, ok bool, err error) {
 	return c.boundClient.GetJSONValue(key, contextSet)
 }
 
+// GetJSONValueCtx is like GetJSONValue but stops waiting for initialization when ctx is done.
+func (c *Client) GetJSONValueCtx(ctx context.Context, key string, contextSet ContextSet) (value interface{}, ok bool, err error) {
+	return c.boundClient.GetJSONValueCtx(ctx, key, contextSet)
+}
+
 // GetIntValueWithDefault returns an int value for a given key and context, with a default value if the key does not exist
 func (c *Client) GetIntValueWithDefault(key string, contextSet ContextSet, defaultValue int64) (value int64, wasFound bool) {
 	return c.boundClient.GetIntValueWithDefault(key, contextSet, defaultValue)
 }
 
+// GetIntValueWithDefaultCtx is like GetIntValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *Client) GetIntValueWithDefaultCtx(ctx context.Context, key string, contextSet ContextSet, defaultValue int64) (value int64, wasFound bool) {
+	return c.boundClient.GetIntValueWithDefaultCtx(ctx, key, contextSet, defaultValue)
+}
+
 // GetBoolValueWithDefault returns a bool value for a given key and context, with a default value if the key does not exist
 func (c *Client) GetBoolValueWithDefault(key string, contextSet ContextSet, defaultValue bool) (value bool, wasFound bool) {
 	return c.boundClient.GetBoolValueWithDefault(key, contextSet, defaultValue)
 }
 
+// GetBoolValueWithDefaultCtx is like GetBoolValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *Client) GetBoolValueWithDefaultCtx(ctx context.Context, key string, contextSet ContextSet, defaultValue bool) (value bool, wasFound bool) {
+	return c.boundClient.GetBoolValueWithDefaultCtx(ctx, key, contextSet, defaultValue)
+}
+
 // GetStringValueWithDefault returns a string value for a given key and context, with a default value if the key does not exist
 func (c *Client) GetStringValueWithDefault(key string, contextSet ContextSet, defaultValue string) (value string, wasFound bool) {
 	return c.boundClient.GetStringValueWithDefault(key, contextSet, defaultValue)
 }
 
+// GetStringValueWithDefaultCtx is like GetStringValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *Client) GetStringValueWithDefaultCtx(ctx context.Context, key string, contextSet ContextSet, defaultValue string) (value string, wasFound bool) {
+	return c.boundClient.GetStringValueWithDefaultCtx(ctx, key, contextSet, defaultValue)
+}
+
 // GetFloatValueWithDefault returns a float value for a given key and context, with a default value if the key does not exist
 func (c *Client) GetFloatValueWithDefault(key string, contextSet ContextSet, defaultValue float64) (value float64, wasFound bool) {
 	return c.boundClient.GetFloatValueWithDefault(key, contextSet, defaultValue)
 }
 
+// GetFloatValueWithDefaultCtx is like GetFloatValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *Client) GetFloatValueWithDefaultCtx(ctx context.Context, key string, contextSet ContextSet, defaultValue float64) (value float64, wasFound bool) {
+	return c.boundClient.GetFloatValueWithDefaultCtx(ctx, key, contextSet, defaultValue)
+}
+
 // GetStringSliceValueWithDefault returns a string slice value for a given key and context, with a default value if the key does not exist
 func (c *Client) GetStringSliceValueWithDefault(key string, contextSet ContextSet, defaultValue []string) (value []string, wasFound bool) {
 	return c.boundClient.GetStringSliceValueWithDefault(key, contextSet, defaultValue)
 }
 
+// GetStringSliceValueWithDefaultCtx is like GetStringSliceValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *Client) GetStringSliceValueWithDefaultCtx(ctx context.Context, key string, contextSet ContextSet, defaultValue []string) (value []string, wasFound bool) {
+	return c.boundClient.GetStringSliceValueWithDefaultCtx(ctx, key, contextSet, defaultValue)
+}
+
 // GetDurationWithDefault returns a duration value for a given key and context, with a default value if the key does not exist
 func (c *Client) GetDurationWithDefault(key string, contextSet ContextSet, defaultValue time.Duration) (value time.Duration, wasFound bool) {
 	return c.boundClient.GetDurationWithDefault(key, contextSet, defaultValue)
 }
 
+// GetDurationWithDefaultCtx is like GetDurationWithDefault but stops waiting for initialization when ctx is done.
+func (c *Client) GetDurationWithDefaultCtx(ctx context.Context, key string, contextSet ContextSet, defaultValue time.Duration) (value time.Duration, wasFound bool) {
+	return c.boundClient.GetDurationWithDefaultCtx(ctx, key, contextSet, defaultValue)
+}
+
 // GetJSONValueWithDefault returns a JSON value for a given key and context, with a default value if the key does not exist
 func (c *Client) GetJSONValueWithDefault(key string, contextSet ContextSet, defaultValue interface{}) (value interface{}, wasFound bool) {
 	return c.boundClient.GetJSONValueWithDefault(key, contextSet, defaultValue)
 }
 
+// GetJSONValueWithDefaultCtx is like GetJSONValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *Client) GetJSONValueWithDefaultCtx(ctx context.Context, key string, contextSet ContextSet, defaultValue interface{}) (value interface{}, wasFound bool) {
+	return c.boundClient.GetJSONValueWithDefaultCtx(ctx, key, contextSet, defaultValue)
+}
+
 // FeatureIsOn returns a bool indicating if a feature is on for a given key and context. It will default to false if the key does not exist.
 func (c *Client) FeatureIsOn(key string, contextSet ContextSet) (result bool, wasFound bool) {
 	return c.boundClient.FeatureIsOn(key, contextSet)
 }
 
+// FeatureIsOnCtx is like FeatureIsOn but stops waiting for initialization when ctx is done.
+func (c *Client) FeatureIsOnCtx(ctx context.Context, key string, contextSet ContextSet) (result bool, wasFound bool) {
+	return c.boundClient.FeatureIsOnCtx(ctx, key, contextSet)
+}
+
 // GetLogLevelStringValue returns a string value for a given key and context, representing a log level.
 func (c *Client) GetLogLevelStringValue(key string, contextSet ContextSet) (result string, ok bool, err error) {
 	return c.boundClient.GetLogLevelStringValue(key, contextSet)
 }
 
+// GetLogLevelStringValueCtx is like GetLogLevelStringValue but stops waiting for initialization when ctx is done.
+func (c *Client) GetLogLevelStringValueCtx(ctx context.Context, key string, contextSet ContextSet) (result string, ok bool, err error) {
+	return c.boundClient.GetLogLevelStringValueCtx(ctx, key, contextSet)
+}
+
 // WithContext returns a new ContextBoundClient bound to the provided context (merged with the parent context)
 func (c *Client) WithContext(contextSet *ContextSet) *ContextBoundClient {
 	mergedContext := contexts.Merge(c.options.GlobalContext, contextSet)
@@ -250,16 +540,28 @@ func (c *Client) GetConfigMatch(key string, contextSet ContextSet) (*ConfigMatch
 	return c.boundClient.GetConfigMatch(key, contextSet)
 }
 
+// GetConfigMatchCtx is like GetConfigMatch but stops waiting for initialization when ctx is done.
+func (c *Client) GetConfigMatchCtx(ctx context.Context, key string, contextSet ContextSet) (*ConfigMatch, error) {
+	return c.boundClient.GetConfigMatchCtx(ctx, key, contextSet)
+}
+
 // Keys returns a list of all keys in the config store
 func (c *Client) Keys() ([]string, error) {
-	if c.awaitInitialization() == timeout {
+	if c.isClosed() {
+		return []string{}, ErrClientClosed
+	}
+
+	switch c.awaitInitialization(context.Background()) {
+	case clientClosed:
+		return []string{}, ErrClientClosed
+	case timeout:
 		switch c.options.OnInitializationFailure {
 		case ReturnNilMatch:
 			c.closeInitializationCompleteOnce.Do(func() {
 				close(c.initializationComplete)
 			})
 		case ReturnError:
-			return []string{}, errors.New("initialization timeout")
+			return []string{}, ErrInitializationTimeout
 		}
 	}
 
@@ -271,14 +573,14 @@ func (c *Client) GetInstanceHash() string {
 	return c.instanceHash
 }
 
-func clientInternalGetValueFunc[T any](contextBoundClient *ContextBoundClient, key string, contextSet contexts.ContextSet, parseFunc func(*prefabProto.ConfigValue) (T, bool)) (T, bool, error) {
+func clientInternalGetValueFunc[T any](ctx context.Context, contextBoundClient *ContextBoundClient, key string, contextSet contexts.ContextSet, parseFunc func(*prefabProto.ConfigValue) (T, bool)) (T, bool, error) {
 	var zeroValue T
 
 	mergedContextSet := *contexts.Merge(contextBoundClient.context, &contextSet)
 
 	contextBoundClient.client.telemetry.RecordContext(&mergedContextSet)
 
-	fetchResult, fetchOk, fetchErr := contextBoundClient.fetchAndProcessValue(key, mergedContextSet, func(cv *prefabProto.ConfigValue) (any, bool) {
+	fetchResult, fetchOk, fetchErr := contextBoundClient.fetchAndProcessValue(ctx, key, mergedContextSet, func(cv *prefabProto.ConfigValue) (any, bool) {
 		pVal, pOk := clientParseValueWrapper(cv, parseFunc)
 		if !pOk {
 			return nil, false
@@ -314,6 +616,19 @@ func (c *Client) SendTelemetry(waitOnQueueToDrain bool) error {
 	return c.telemetry.Submit(waitOnQueueToDrain)
 }
 
+// TelemetryStats returns how much telemetry has been dropped since the client
+// was created. Drops are also reported to Prefab as client stats.
+func (c *Client) TelemetryStats() TelemetryStats {
+	return c.telemetry.Stats()
+}
+
+// RecordLog counts a message logged by loggerName at level for logger
+// telemetry. It's a no-op unless WithCollectLoggerCounts is enabled, and is
+// meant to be called by logging bridges.
+func (c *Client) RecordLog(loggerName string, level prefabProto.LogLevel) {
+	c.telemetry.RecordLog(loggerName, level)
+}
+
 // SendTelemetry sends telemetry data to the Prefab Cloud API. If
 // waitOnQueueToDrain is true, the method will block until the telemetry queue
 // is empty. You likely don't want to waitOnQueueToDrain in a production
@@ -325,7 +640,12 @@ func (c *ContextBoundClient) SendTelemetry(waitOnQueueToDrain bool) error {
 
 // GetIntValueWithDefault returns an int value for a given key and context, with a default value if the key does not exist
 func (c *ContextBoundClient) GetIntValueWithDefault(key string, contextSet contexts.ContextSet, defaultValue int64) (value int64, wasFound bool) {
-	value, ok, err := c.GetIntValue(key, contextSet)
+	return c.GetIntValueWithDefaultCtx(context.Background(), key, contextSet, defaultValue)
+}
+
+// GetIntValueWithDefaultCtx is like GetIntValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetIntValueWithDefaultCtx(ctx context.Context, key string, contextSet contexts.ContextSet, defaultValue int64) (value int64, wasFound bool) {
+	value, ok, err := c.GetIntValueCtx(ctx, key, contextSet)
 	if err != nil || !ok {
 		return defaultValue, true
 	}
@@ -335,12 +655,22 @@ func (c *ContextBoundClient) GetIntValueWithDefault(key string, contextSet conte
 
 // GetIntValue returns an int value for a given key and context
 func (c *ContextBoundClient) GetIntValue(key string, contextSet contexts.ContextSet) (value int64, ok bool, err error) {
-	return clientInternalGetValueFunc(c, key, contextSet, utils.ExtractIntValue)
+	return c.GetIntValueCtx(context.Background(), key, contextSet)
+}
+
+// GetIntValueCtx is like GetIntValue but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetIntValueCtx(ctx context.Context, key string, contextSet contexts.ContextSet) (value int64, ok bool, err error) {
+	return clientInternalGetValueFunc(ctx, c, key, contextSet, utils.ExtractIntValue)
 }
 
 // GetStringValueWithDefault returns a string value for a given key and context, with a default value if the key does not exist
 func (c *ContextBoundClient) GetStringValueWithDefault(key string, contextSet contexts.ContextSet, defaultValue string) (value string, wasFound bool) {
-	value, ok, err := c.GetStringValue(key, contextSet)
+	return c.GetStringValueWithDefaultCtx(context.Background(), key, contextSet, defaultValue)
+}
+
+// GetStringValueWithDefaultCtx is like GetStringValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetStringValueWithDefaultCtx(ctx context.Context, key string, contextSet contexts.ContextSet, defaultValue string) (value string, wasFound bool) {
+	value, ok, err := c.GetStringValueCtx(ctx, key, contextSet)
 	if err != nil || !ok {
 		return defaultValue, true
 	}
@@ -350,17 +680,32 @@ func (c *ContextBoundClient) GetStringValueWithDefault(key string, contextSet co
 
 // GetStringValue returns a string value for a given key and context
 func (c *ContextBoundClient) GetStringValue(key string, contextSet contexts.ContextSet) (value string, ok bool, err error) {
-	return clientInternalGetValueFunc(c, key, contextSet, utils.ExtractStringValue)
+	return c.GetStringValueCtx(context.Background(), key, contextSet)
+}
+
+// GetStringValueCtx is like GetStringValue but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetStringValueCtx(ctx context.Context, key string, contextSet contexts.ContextSet) (value string, ok bool, err error) {
+	return clientInternalGetValueFunc(ctx, c, key, contextSet, utils.ExtractStringValue)
 }
 
 // GetJSONValue returns a JSON value for a given key and context
 func (c *ContextBoundClient) GetJSONValue(key string, contextSet contexts.ContextSet) (value interface{}, ok bool, err error) {
-	return clientInternalGetValueFunc(c, key, contextSet, utils.ExtractJSONValueWithoutError)
+	return c.GetJSONValueCtx(context.Background(), key, contextSet)
+}
+
+// GetJSONValueCtx is like GetJSONValue but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetJSONValueCtx(ctx context.Context, key string, contextSet contexts.ContextSet) (value interface{}, ok bool, err error) {
+	return clientInternalGetValueFunc(ctx, c, key, contextSet, utils.ExtractJSONValueWithoutError)
 }
 
 // GetJSONValueWithDefault returns a JSON value for a given key and context, with a default value if the key does not exist
 func (c *ContextBoundClient) GetJSONValueWithDefault(key string, contextSet contexts.ContextSet, defaultValue interface{}) (value interface{}, wasFound bool) {
-	value, ok, err := c.GetJSONValue(key, contextSet)
+	return c.GetJSONValueWithDefaultCtx(context.Background(), key, contextSet, defaultValue)
+}
+
+// GetJSONValueWithDefaultCtx is like GetJSONValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetJSONValueWithDefaultCtx(ctx context.Context, key string, contextSet contexts.ContextSet, defaultValue interface{}) (value interface{}, wasFound bool) {
+	value, ok, err := c.GetJSONValueCtx(ctx, key, contextSet)
 	if err != nil || !ok {
 		return defaultValue, true
 	}
@@ -370,14 +715,24 @@ func (c *ContextBoundClient) GetJSONValueWithDefault(key string, contextSet cont
 
 // FeatureIsOn returns a bool indicating if a feature is on for a given key and context. It will default to false if the key does not exist.
 func (c *ContextBoundClient) FeatureIsOn(key string, contextSet contexts.ContextSet) (result bool, wasFound bool) {
-	value, ok := c.GetBoolValueWithDefault(key, contextSet, false)
+	return c.FeatureIsOnCtx(context.Background(), key, contextSet)
+}
+
+// FeatureIsOnCtx is like FeatureIsOn but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) FeatureIsOnCtx(ctx context.Context, key string, contextSet contexts.ContextSet) (result bool, wasFound bool) {
+	value, ok := c.GetBoolValueWithDefaultCtx(ctx, key, contextSet, false)
 
 	return value, ok
 }
 
 // GetLogLevelStringValue returns a string value for a given key and context, representing a log level.
 func (c *ContextBoundClient) GetLogLevelStringValue(key string, contextSet contexts.ContextSet) (value string, ok bool, err error) {
-	rawValue, ok, err := clientInternalGetValueFunc(c, key, contextSet, utils.ExtractLogLevelValue)
+	return c.GetLogLevelStringValueCtx(context.Background(), key, contextSet)
+}
+
+// GetLogLevelStringValueCtx is like GetLogLevelStringValue but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetLogLevelStringValueCtx(ctx context.Context, key string, contextSet contexts.ContextSet) (value string, ok bool, err error) {
+	rawValue, ok, err := clientInternalGetValueFunc(ctx, c, key, contextSet, utils.ExtractLogLevelValue)
 
 	if err != nil || !ok {
 		return "", false, err
@@ -388,7 +743,12 @@ func (c *ContextBoundClient) GetLogLevelStringValue(key string, contextSet conte
 
 // GetBoolValueWithDefault returns a bool value for a given key and context, with a default value if the key does not exist
 func (c *ContextBoundClient) GetBoolValueWithDefault(key string, contextSet contexts.ContextSet, defaultValue bool) (value bool, wasFound bool) {
-	value, ok, err := c.GetBoolValue(key, contextSet)
+	return c.GetBoolValueWithDefaultCtx(context.Background(), key, contextSet, defaultValue)
+}
+
+// GetBoolValueWithDefaultCtx is like GetBoolValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetBoolValueWithDefaultCtx(ctx context.Context, key string, contextSet contexts.ContextSet, defaultValue bool) (value bool, wasFound bool) {
+	value, ok, err := c.GetBoolValueCtx(ctx, key, contextSet)
 	if err != nil || !ok {
 		return defaultValue, true
 	}
@@ -398,12 +758,22 @@ func (c *ContextBoundClient) GetBoolValueWithDefault(key string, contextSet cont
 
 // GetBoolValue returns a bool value for a given key and context
 func (c *ContextBoundClient) GetBoolValue(key string, contextSet contexts.ContextSet) (value bool, ok bool, err error) {
-	return clientInternalGetValueFunc(c, key, contextSet, utils.ExtractBoolValue)
+	return c.GetBoolValueCtx(context.Background(), key, contextSet)
+}
+
+// GetBoolValueCtx is like GetBoolValue but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetBoolValueCtx(ctx context.Context, key string, contextSet contexts.ContextSet) (value bool, ok bool, err error) {
+	return clientInternalGetValueFunc(ctx, c, key, contextSet, utils.ExtractBoolValue)
 }
 
 // GetFloatValueWithDefault returns a float value for a given key and context, with a default value if the key does not exist
 func (c *ContextBoundClient) GetFloatValueWithDefault(key string, contextSet contexts.ContextSet, defaultValue float64) (value float64, wasFound bool) {
-	value, ok, err := c.GetFloatValue(key, contextSet)
+	return c.GetFloatValueWithDefaultCtx(context.Background(), key, contextSet, defaultValue)
+}
+
+// GetFloatValueWithDefaultCtx is like GetFloatValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetFloatValueWithDefaultCtx(ctx context.Context, key string, contextSet contexts.ContextSet, defaultValue float64) (value float64, wasFound bool) {
+	value, ok, err := c.GetFloatValueCtx(ctx, key, contextSet)
 	if err != nil || !ok {
 		return defaultValue, true
 	}
@@ -413,12 +783,22 @@ func (c *ContextBoundClient) GetFloatValueWithDefault(key string, contextSet con
 
 // GetFloatValue returns a float value for a given key and context
 func (c *ContextBoundClient) GetFloatValue(key string, contextSet contexts.ContextSet) (value float64, ok bool, err error) {
-	return clientInternalGetValueFunc(c, key, contextSet, utils.ExtractFloatValue)
+	return c.GetFloatValueCtx(context.Background(), key, contextSet)
+}
+
+// GetFloatValueCtx is like GetFloatValue but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetFloatValueCtx(ctx context.Context, key string, contextSet contexts.ContextSet) (value float64, ok bool, err error) {
+	return clientInternalGetValueFunc(ctx, c, key, contextSet, utils.ExtractFloatValue)
 }
 
 // GetStringSliceValueWithDefault returns a string slice value for a given key and context, with a default value if the key does not exist
 func (c *ContextBoundClient) GetStringSliceValueWithDefault(key string, contextSet contexts.ContextSet, defaultValue []string) (value []string, wasFound bool) {
-	value, ok, err := c.GetStringSliceValue(key, contextSet)
+	return c.GetStringSliceValueWithDefaultCtx(context.Background(), key, contextSet, defaultValue)
+}
+
+// GetStringSliceValueWithDefaultCtx is like GetStringSliceValueWithDefault but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetStringSliceValueWithDefaultCtx(ctx context.Context, key string, contextSet contexts.ContextSet, defaultValue []string) (value []string, wasFound bool) {
+	value, ok, err := c.GetStringSliceValueCtx(ctx, key, contextSet)
 	if err != nil || !ok {
 		return defaultValue, true
 	}
@@ -428,12 +808,22 @@ func (c *ContextBoundClient) GetStringSliceValueWithDefault(key string, contextS
 
 // GetStringSliceValue returns a string slice value for a given key and context
 func (c *ContextBoundClient) GetStringSliceValue(key string, contextSet contexts.ContextSet) (value []string, ok bool, err error) {
-	return clientInternalGetValueFunc(c, key, contextSet, utils.ExtractStringListValue)
+	return c.GetStringSliceValueCtx(context.Background(), key, contextSet)
+}
+
+// GetStringSliceValueCtx is like GetStringSliceValue but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetStringSliceValueCtx(ctx context.Context, key string, contextSet contexts.ContextSet) (value []string, ok bool, err error) {
+	return clientInternalGetValueFunc(ctx, c, key, contextSet, utils.ExtractStringListValue)
 }
 
 // GetDurationWithDefault returns a duration value for a given key and context, with a default value if the key does not exist
 func (c *ContextBoundClient) GetDurationWithDefault(key string, contextSet contexts.ContextSet, defaultValue time.Duration) (value time.Duration, wasFound bool) {
-	value, ok, err := c.GetDurationValue(key, contextSet)
+	return c.GetDurationWithDefaultCtx(context.Background(), key, contextSet, defaultValue)
+}
+
+// GetDurationWithDefaultCtx is like GetDurationWithDefault but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetDurationWithDefaultCtx(ctx context.Context, key string, contextSet contexts.ContextSet, defaultValue time.Duration) (value time.Duration, wasFound bool) {
+	value, ok, err := c.GetDurationValueCtx(ctx, key, contextSet)
 	if err != nil || !ok {
 		return defaultValue, true
 	}
@@ -443,11 +833,16 @@ func (c *ContextBoundClient) GetDurationWithDefault(key string, contextSet conte
 
 // GetDurationValue returns a duration value for a given key and context
 func (c *ContextBoundClient) GetDurationValue(key string, contextSet contexts.ContextSet) (value time.Duration, ok bool, err error) {
-	return clientInternalGetValueFunc(c, key, contextSet, utils.ExtractDurationValue)
+	return c.GetDurationValueCtx(context.Background(), key, contextSet)
 }
 
-func (c *ContextBoundClient) fetchAndProcessValue(key string, contextSet contexts.ContextSet, parser utils.ExtractValueFunction) (any, bool, error) {
-	getResult, err := c.client.internalGetValue(key, contextSet)
+// GetDurationValueCtx is like GetDurationValue but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetDurationValueCtx(ctx context.Context, key string, contextSet contexts.ContextSet) (value time.Duration, ok bool, err error) {
+	return clientInternalGetValueFunc(ctx, c, key, contextSet, utils.ExtractDurationValue)
+}
+
+func (c *ContextBoundClient) fetchAndProcessValue(ctx context.Context, key string, contextSet contexts.ContextSet, parser utils.ExtractValueFunction) (any, bool, error) {
+	getResult, err := c.client.internalGetValue(ctx, key, contextSet)
 	if err != nil {
 		return nil, false, err
 	}
@@ -478,8 +873,13 @@ func (c *ContextBoundClient) GetConfig(key string) (*prefabProto.Config, bool) {
 
 // GetConfigMatch returns a ConfigMatch object for a given key and context. You're unlikely to need this method.
 func (c *ContextBoundClient) GetConfigMatch(key string, contextSet ContextSet) (*ConfigMatch, error) {
+	return c.GetConfigMatchCtx(context.Background(), key, contextSet)
+}
+
+// GetConfigMatchCtx is like GetConfigMatch but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetConfigMatchCtx(ctx context.Context, key string, contextSet ContextSet) (*ConfigMatch, error) {
 	mergedContextSet := *contexts.Merge(c.context, &contextSet)
-	getResult, err := c.client.internalGetValue(key, mergedContextSet)
+	getResult, err := c.client.internalGetValue(ctx, key, mergedContextSet)
 	if err != nil {
 		return nil, err
 	}
@@ -492,21 +892,43 @@ func (c *ContextBoundClient) GetInstanceHash() string {
 	return c.client.GetInstanceHash()
 }
 
-func (c *Client) internalGetValue(key string, contextSet contexts.ContextSet) (resolutionResult, error) {
-	if c.awaitInitialization() == timeout {
+// awaitEvaluable waits until configs can be evaluated, honoring the
+// OnInitializationFailure option if loading times out.
+func (c *Client) awaitEvaluable(ctx context.Context) error {
+	if c.isClosed() {
+		return ErrClientClosed
+	}
+
+	switch c.awaitInitialization(ctx) {
+	case clientClosed:
+		return ErrClientClosed
+	case contextDone:
+		return ctx.Err()
+	case timeout:
 		switch c.options.OnInitializationFailure {
 		case optionsPkg.ReturnNilMatch:
 			c.closeInitializationCompleteOnce.Do(func() {
 				close(c.initializationComplete)
 			})
 		case optionsPkg.ReturnError:
-			return resolutionResultError(), errors.New("initialization timeout")
+			return ErrInitializationTimeout
 		}
 	}
 
+	return nil
+}
+
+func (c *Client) internalGetValue(ctx context.Context, key string, contextSet contexts.ContextSet) (resolutionResult, error) {
+	if err := c.awaitEvaluable(ctx); err != nil {
+		return resolutionResultError(), err
+	}
+
 	match, err := c.configResolver.ResolveValue(key, &contextSet)
 	if err != nil {
-		return resolutionResultError(), err
+		result := resolutionResultError()
+		result.match = match
+
+		return result, err
 	}
 
 	c.telemetry.RecordEvaluation(match)
@@ -551,10 +973,21 @@ func clientParseValueWrapper[T any](cv *prefabProto.ConfigValue, parseFunc func(
 	return zeroValue, false
 }
 
-func (c *Client) awaitInitialization() awaitInitializationResult {
+func (c *Client) awaitInitialization(ctx context.Context) awaitInitializationResult {
+	// Prefer an already-completed initialization over an already-done context.
+	select {
+	case <-c.initializationComplete:
+		return success
+	default:
+	}
+
 	select {
 	case <-c.initializationComplete:
 		return success
+	case <-c.closed:
+		return clientClosed
+	case <-ctx.Done():
+		return contextDone
 	case <-time.After(time.Duration(c.options.InitializationTimeoutSeconds) * time.Second):
 		slog.Warn(fmt.Sprintf("%f second timeout expired, proceeding without waiting further. Configure in options `InitializationTimeoutSeconds`", c.options.InitializationTimeoutSeconds))
 
@@ -567,6 +1000,8 @@ type awaitInitializationResult int
 const (
 	success awaitInitializationResult = iota
 	timeout
+	clientClosed
+	contextDone
 )
 
 // ExtractValue extracts the underlying value from a ConfigValue. You're unlikely to need this method.
@@ -1,14 +1,23 @@
 package prefab_test
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	integrationtestsupport "github.com/prefab-cloud/prefab-cloud-go/pkg/internal/integration_test_support"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
 
 func TestWithConfig(t *testing.T) {
@@ -85,6 +94,16 @@ func TestCannotUseWithConfigAndOtherSources(t *testing.T) {
 	assert.Equal(t, "cannot use WithConfigs with other sources", err.Error())
 }
 
+func TestPollSourceNeedsExcludeDefault(t *testing.T) {
+	_, err := prefab.NewClient(
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithSources([]string{"poll://"}, false),
+		prefab.WithAllTelemetryDisabled())
+
+	require.Error(t, err)
+	assert.Equal(t, "poll:// sources replace the default API source, so they need excludeDefault", err.Error())
+}
+
 func TestWithAJSONConfigDump(t *testing.T) {
 	t.Setenv("PREFAB_DATAFILE", "testdata/download.json")
 
@@ -156,3 +175,254 @@ func TestGetConfigMatchWithAJSONConfigDumpAndGlobalContext(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "default", str)
 }
+
+func TestNewClientClosesStoresWhenASourceFails(t *testing.T) {
+	requested := make(chan struct{}, 1)
+	cancelled := make(chan struct{}, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		requested <- struct{}{}
+
+		<-r.Context().Done()
+		cancelled <- struct{}{}
+	}))
+	defer server.Close()
+
+	_, err := prefab.NewClient(
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithAPIURLs([]string{server.URL}),
+		prefab.WithSources([]string{"poll://", "datafile://" + filepath.Join(t.TempDir(), "missing.yaml")}, true),
+		prefab.WithAllTelemetryDisabled())
+	require.Error(t, err)
+
+	select {
+	case <-requested:
+		select {
+		case <-cancelled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the polling store built before the failure was never closed")
+		}
+	case <-time.After(time.Second):
+		// closed before it got as far as loading
+	}
+}
+
+func TestCloseMakesGettersReturnErrClientClosed(t *testing.T) {
+	client, err := prefab.NewClient(
+		prefab.WithConfigs(map[string]interface{}{"string.key": "value"}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	str, ok, err := client.GetStringValue("string.key", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "value", str)
+
+	require.NoError(t, client.Close())
+
+	_, ok, err = client.GetStringValue("string.key", prefab.ContextSet{})
+	require.ErrorIs(t, err, prefab.ErrClientClosed)
+	assert.False(t, ok)
+
+	_, err = client.Keys()
+	require.ErrorIs(t, err, prefab.ErrClientClosed)
+
+	// Closing again is a no-op
+	require.NoError(t, client.Close())
+}
+
+func TestShutdownStopsRetriesAndFlushesTelemetry(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer apiServer.Close()
+
+	telemetryRequests, telemetryServer := integrationtestsupport.StartTestServer()
+	defer telemetryServer.Close()
+
+	client, err := prefab.NewClient(
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithAPIURLs([]string{apiServer.URL}),
+		prefab.WithTelemetryHost(telemetryServer.URL),
+		prefab.WithTelemetrySyncInterval(time.Hour),
+		prefab.WithInitializationTimeoutSeconds(0.1),
+		prefab.WithOnInitializationFailure(prefab.ReturnNilMatch))
+	require.NoError(t, err)
+
+	contextSet := prefab.NewContextSet().WithNamedContextValues("user", map[string]interface{}{"key": "u123"})
+	_, _, _ = client.GetStringValue("does.not.exist", *contextSet)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, client.Shutdown(ctx))
+
+	// The final submission sent the example context recorded above
+	assert.Len(t, *telemetryRequests, 1)
+
+	_, _, err = client.GetStringValue("does.not.exist", *contextSet)
+	require.ErrorIs(t, err, prefab.ErrClientClosed)
+}
+
+func TestCtxGettersRespectDeadlineWhileInitializing(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer apiServer.Close()
+
+	client, err := prefab.NewClient(
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithAPIURLs([]string{apiServer.URL}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, ok, err := client.GetIntValueCtx(ctx, "some.key", prefab.ContextSet{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, ok)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	require.ErrorIs(t, client.WaitForReady(ctx), context.DeadlineExceeded)
+
+	_, err = client.WithContext(prefab.NewContextSet()).GetConfigMatchCtx(ctx, "some.key", prefab.ContextSet{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	value, _ := client.GetStringValueWithDefaultCtx(ctx, "some.key", prefab.ContextSet{}, "default")
+	assert.Equal(t, "default", value)
+
+	on, _ := client.WithContext(prefab.NewContextSet()).FeatureIsOnCtx(ctx, "some.key", prefab.ContextSet{})
+	assert.False(t, on)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestKeysReturnsInitializationTimeout(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer apiServer.Close()
+
+	client, err := prefab.NewClient(
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithAPIURLs([]string{apiServer.URL}),
+		prefab.WithInitializationTimeoutSeconds(0.05),
+		prefab.WithOnInitializationFailure(prefab.ReturnError),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Keys()
+	require.ErrorIs(t, err, prefab.ErrInitializationTimeout)
+}
+
+func TestWaitForReady(t *testing.T) {
+	client, err := prefab.NewClient(
+		prefab.WithConfigs(map[string]interface{}{"int.key": int64(42)}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	require.NoError(t, client.WaitForReady(context.Background()))
+
+	// A cancelled context doesn't matter once the client is ready
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	i, ok, err := client.GetIntValueCtx(ctx, "int.key", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), i)
+}
+
+func stringConfig(t *testing.T, key string, id int64, value string) *prefabProto.Config {
+	t.Helper()
+
+	return &prefabProto.Config{
+		Key:        key,
+		Id:         id,
+		ConfigType: prefabProto.ConfigType_CONFIG,
+		ValueType:  prefabProto.Config_STRING,
+		Rows: []*prefabProto.ConfigRow{
+			{
+				ProjectEnvId: internal.Int64Ptr(101),
+				Values: []*prefabProto.ConditionalValue{
+					{Value: testutils.CreateConfigValueAndAssertOk(t, value)},
+				},
+			},
+		},
+	}
+}
+
+// testConfig builds a config of configType with a single row holding values.
+func testConfig(key string, id int64, configType prefabProto.ConfigType, values ...*prefabProto.ConditionalValue) *prefabProto.Config {
+	return &prefabProto.Config{
+		Key:        key,
+		Id:         id,
+		ConfigType: configType,
+		Rows:       []*prefabProto.ConfigRow{{Values: values}},
+	}
+}
+
+// always is a conditional value that matches every context.
+func always(value *prefabProto.ConfigValue) *prefabProto.ConditionalValue {
+	return &prefabProto.ConditionalValue{Value: value}
+}
+
+// newTestClient starts a fake API serving configs and returns a ready client
+// using it, along with the channel that streams updates to the client.
+func newTestClient(t *testing.T, configs ...*prefabProto.Config) (*prefab.Client, chan<- *prefabProto.Configs) {
+	t.Helper()
+
+	return newTestClientWithOptions(t, configs)
+}
+
+// newTestClientWithOptions is newTestClient with opts added to the client's
+// options.
+func newTestClientWithOptions(t *testing.T, configs []*prefabProto.Config, opts ...prefab.Option) (*prefab.Client, chan<- *prefabProto.Configs) {
+	t.Helper()
+
+	server, updates := integrationtestsupport.StartFakeAPIServer(t, &prefabProto.Configs{
+		Configs: configs,
+		// the environment stringConfig's rows are for
+		ConfigServicePointer: &prefabProto.ConfigServicePointer{ProjectEnvId: 101},
+	})
+
+	client, err := prefab.NewClient(append([]prefab.Option{
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithAPIURLs([]string{server.URL}),
+		prefab.WithAllTelemetryDisabled(),
+	}, opts...)...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { client.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, client.WaitForReady(ctx))
+
+	return client, updates
+}
+
+// sendUpdate streams configs to the client once it has connected.
+func sendUpdate(t *testing.T, updates chan<- *prefabProto.Configs, configs *prefabProto.Configs) {
+	t.Helper()
+
+	select {
+	case updates <- configs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SSE connection was never opened")
+	}
+}
+
+type mapEnvLookup map[string]string
+
+func (m mapEnvLookup) LookupEnv(key string) (string, bool) {
+	value, ok := m[key]
+
+	return value, ok
+}
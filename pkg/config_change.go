@@ -0,0 +1,169 @@
+package prefab
+
+import (
+	"log/slog"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/contexts"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/utils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// ChangeEvent describes a config being added, updated or deleted after an
+// update from the API (the initial load or SSE). Old is nil for additions and
+// New is nil for deletions.
+type ChangeEvent = internal.ChangeEvent
+
+// ChangeType says whether a ChangeEvent is an addition, update or deletion.
+type ChangeType = internal.ChangeType
+
+const (
+	// ChangeTypeAdded is used when a key appears for the first time
+	ChangeTypeAdded = internal.ChangeTypeAdded
+	// ChangeTypeUpdated is used when a newer version of a config replaces an existing one
+	ChangeTypeUpdated = internal.ChangeTypeUpdated
+	// ChangeTypeDeleted is used when a config is tombstoned
+	ChangeTypeDeleted = internal.ChangeTypeDeleted
+)
+
+// watchBufferSize is how many events a Watch channel holds before new events are dropped.
+const watchBufferSize = 16
+
+// OnConfigChange registers listener to be called for every config change. The
+// listener runs on the goroutine applying the update, so it should not block.
+// Call the returned function to stop receiving events.
+func (c *Client) OnConfigChange(listener func(ChangeEvent)) (unsubscribe func()) {
+	return c.changeNotifier.Subscribe(listener)
+}
+
+// OnConfigUpdate registers listener to be called once per update from the API
+// with all of the update's changes, instead of once per change like
+// OnConfigChange. The listener runs on the goroutine applying the update, so
+// it should not block. Call the returned function to stop receiving events.
+func (c *Client) OnConfigUpdate(listener func([]ChangeEvent)) (unsubscribe func()) {
+	return c.changeNotifier.SubscribeBatch(listener)
+}
+
+// Watch returns a channel that receives change events for key. Events are
+// dropped (and a warning logged) if the channel's buffer is full. The channel
+// is closed when stop is called or the client is closed.
+func (c *Client) Watch(key string) (events <-chan ChangeEvent, stop func()) {
+	eventChannel := make(chan ChangeEvent, watchBufferSize)
+	done := make(chan struct{})
+
+	var (
+		mutex   sync.Mutex
+		stopped bool
+	)
+
+	unsubscribe := c.changeNotifier.Subscribe(func(event ChangeEvent) {
+		if event.Key != key {
+			return
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if stopped {
+			return
+		}
+
+		select {
+		case eventChannel <- event:
+		default:
+			slog.Warn("dropping config change event, watch channel is full", "key", key)
+		}
+	})
+
+	stop = func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if stopped {
+			return
+		}
+
+		stopped = true
+
+		unsubscribe()
+		close(eventChannel)
+		close(done)
+	}
+
+	go func() {
+		select {
+		case <-c.closed:
+			stop()
+		case <-done:
+		}
+	}()
+
+	return eventChannel, stop
+}
+
+// WatchValue calls onChange whenever the value key evaluates to for contextSet
+// changes. The value is re-evaluated once after every config update, so
+// changes to segments or other configs it depends on are picked up too.
+// Values are passed the way ExtractValue returns them; a key that doesn't
+// exist (or has no matching rule) is nil. Calls to onChange are never
+// concurrent and arrive in the order the changes happened. Call the returned
+// function to stop watching.
+func (c *Client) WatchValue(key string, contextSet ContextSet, onChange func(oldValue, newValue any)) (stop func()) {
+	return c.boundClient.WatchValue(key, contextSet, onChange)
+}
+
+// WatchValue is like Client.WatchValue, evaluating with the bound context merged in.
+func (c *ContextBoundClient) WatchValue(key string, contextSet ContextSet, onChange func(oldValue, newValue any)) (stop func()) {
+	mergedContextSet := contexts.Merge(c.context, &contextSet)
+
+	resolve := func() *prefabProto.ConfigValue {
+		match, err := c.client.configResolver.ResolveValue(key, mergedContextSet)
+		if err != nil {
+			return nil
+		}
+
+		return match.Match
+	}
+
+	// held while calling onChange so calls are serialized and in order
+	var (
+		mutex   sync.Mutex
+		current *prefabProto.ConfigValue
+	)
+
+	// subscribe before reading the initial value (with the mutex held, so
+	// updates wait for it) so an update in between isn't missed
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	stop = c.client.changeNotifier.SubscribeBatch(func([]ChangeEvent) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		latest := resolve()
+		if proto.Equal(current, latest) {
+			return
+		}
+
+		previous := current
+		current = latest
+
+		onChange(watchedValue(key, previous), watchedValue(key, latest))
+	})
+
+	current = resolve()
+
+	return stop
+}
+
+func watchedValue(key string, configValue *prefabProto.ConfigValue) any {
+	value, _, err := utils.ExtractValue(configValue)
+	if err != nil {
+		slog.Warn("unable to extract watched value", "key", key, "err", err)
+	}
+
+	return value
+}
@@ -0,0 +1,114 @@
+package prefab_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestWatchAndOnConfigChange(t *testing.T) {
+	fooV1 := stringConfig(t, "foo", 1, "one")
+	barV1 := stringConfig(t, "bar", 2, "bar")
+
+	client, updates := newTestClient(t, fooV1, barV1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	allEvents := make(chan prefab.ChangeEvent, 10)
+	unsubscribe := client.OnConfigChange(func(event prefab.ChangeEvent) { allEvents <- event })
+
+	defer unsubscribe()
+
+	fooEvents, _ := client.Watch("foo")
+
+	fooV2 := stringConfig(t, "foo", 3, "two")
+	bazV1 := stringConfig(t, "baz", 4, "baz")
+	barTombstone := &prefabProto.Config{Key: "bar", Id: 5}
+
+	sendUpdate(t, updates, &prefabProto.Configs{
+		Configs:              []*prefabProto.Config{fooV2, bazV1, barTombstone},
+		ConfigServicePointer: &prefabProto.ConfigServicePointer{ProjectEnvId: 101},
+	})
+
+	receive := func(events <-chan prefab.ChangeEvent) prefab.ChangeEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for a change event")
+
+			return prefab.ChangeEvent{}
+		}
+	}
+
+	event := receive(fooEvents)
+	assert.Equal(t, prefab.ChangeTypeUpdated, event.Type)
+	assert.Equal(t, int64(3), event.ConfigID)
+	assert.True(t, proto.Equal(fooV1, event.Old))
+	assert.True(t, proto.Equal(fooV2, event.New))
+
+	var types []prefab.ChangeType
+	for range 3 {
+		types = append(types, receive(allEvents).Type)
+	}
+
+	assert.Equal(t, []prefab.ChangeType{prefab.ChangeTypeUpdated, prefab.ChangeTypeAdded, prefab.ChangeTypeDeleted}, types)
+
+	value, ok, err := client.GetStringValue("foo", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "two", value)
+
+	require.NoError(t, client.Close())
+
+	_, open := <-fooEvents
+	assert.False(t, open, "watch channel should be closed with the client")
+}
+
+func TestWatchValue(t *testing.T) {
+	limitConfig := func(id int64, tenantAValue int64, defaultValue int64) *prefabProto.Config {
+		isTenantA := &prefabProto.Criterion{
+			PropertyName: "user.tenant",
+			Operator:     prefabProto.Criterion_PROP_IS_ONE_OF,
+			ValueToMatch: testutils.CreateConfigValueAndAssertOk(t, []string{"a"}),
+		}
+
+		return testConfig("limit", id, prefabProto.ConfigType_CONFIG,
+			&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{isTenantA}, Value: testutils.CreateConfigValueAndAssertOk(t, tenantAValue)},
+			always(testutils.CreateConfigValueAndAssertOk(t, defaultValue)))
+	}
+
+	client, updates := newTestClient(t, limitConfig(1, 1, 10))
+
+	type change struct{ old, new any }
+
+	changes := make(chan change, 10)
+	tenantA := prefab.NewContextSet().WithNamedContextValues("user", map[string]interface{}{"tenant": "a"})
+
+	stop := client.WatchValue("limit", *tenantA, func(oldValue, newValue any) { changes <- change{oldValue, newValue} })
+	defer stop()
+
+	// Neither an unrelated config nor a change that only affects other
+	// tenants should fire the callback.
+	for _, update := range []*prefabProto.Config{stringConfig(t, "other", 2, "x"), limitConfig(3, 1, 20), limitConfig(4, 2, 20)} {
+		sendUpdate(t, updates, &prefabProto.Configs{Configs: []*prefabProto.Config{update}})
+	}
+
+	select {
+	case got := <-changes:
+		assert.Equal(t, change{int64(1), int64(2)}, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the value to change")
+	}
+
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,43 @@
+package prefab
+
+import (
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/connection"
+)
+
+// ConnectionState is where the client is in loading configs from the API and
+// following their updates.
+type ConnectionState = connection.State
+
+// ConnectionStatus is a snapshot of the client's connection to the API: its
+// state, when it last synced and the newest config ID it has seen.
+type ConnectionStatus = connection.Status
+
+// ConnectionStateChange is passed to OnStateChange listeners.
+type ConnectionStateChange = connection.StateChange
+
+const (
+	// ConnectionInitializing is used until the configs are loaded from the API
+	ConnectionInitializing = connection.Initializing
+	// ConnectionLoaded is used once the configs are loaded and while updates come by polling
+	ConnectionLoaded = connection.Loaded
+	// ConnectionStreaming is used while the SSE stream is connected
+	ConnectionStreaming = connection.Streaming
+	// ConnectionReconnecting is used while a dropped SSE stream is reopened
+	ConnectionReconnecting = connection.Reconnecting
+	// ConnectionFailed is used when the configs couldn't be loaded from the API
+	ConnectionFailed = connection.Failed
+)
+
+// ConnectionStatus returns the current state of the client's connection to
+// the API, e.g. for a readiness probe.
+func (c *Client) ConnectionStatus() ConnectionStatus {
+	return c.connection.Status()
+}
+
+// OnStateChange registers listener to be called whenever the connection state
+// changes. The listener runs on the goroutine that changed the state, so it
+// should not block. Use WithOnStateChange to also see the changes made while
+// the client starts. Call the returned function to stop receiving changes.
+func (c *Client) OnStateChange(listener func(ConnectionStateChange)) (unsubscribe func()) {
+	return c.connection.Subscribe(listener)
+}
@@ -0,0 +1,113 @@
+package prefab_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	integrationtestsupport "github.com/prefab-cloud/prefab-cloud-go/pkg/internal/integration_test_support"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestConnectionStatus(t *testing.T) {
+	pointer := &prefabProto.ConfigServicePointer{ProjectEnvId: 101}
+
+	// this test drops the stream, so it needs the server itself
+	server, updates := integrationtestsupport.StartFakeAPIServer(t, &prefabProto.Configs{
+		Configs:              []*prefabProto.Config{stringConfig(t, "foo", 1, "one")},
+		ConfigServicePointer: pointer,
+	})
+
+	changes := make(chan prefab.ConnectionStateChange, 10)
+
+	client, err := prefab.NewClient(
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithAPIURLs([]string{server.URL}),
+		prefab.WithAllTelemetryDisabled(),
+		prefab.WithOnStateChange(func(change prefab.ConnectionStateChange) { changes <- change }))
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	require.NoError(t, client.WaitForReady(ctx))
+
+	nextState := func() prefab.ConnectionState {
+		select {
+		case change := <-changes:
+			return change.Status.State
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for a state change")
+
+			return ""
+		}
+	}
+
+	assert.Equal(t, prefab.ConnectionLoaded, nextState())
+
+	status := client.ConnectionStatus()
+	assert.Equal(t, prefab.ConnectionLoaded, status.State)
+	assert.Equal(t, int64(1), status.HighWatermark)
+	assert.False(t, status.LastSync.IsZero())
+
+	sendUpdate(t, updates, &prefabProto.Configs{Configs: []*prefabProto.Config{stringConfig(t, "foo", 2, "two")}, ConfigServicePointer: pointer})
+
+	assert.Equal(t, prefab.ConnectionStreaming, nextState())
+
+	assert.Eventually(t, func() bool { return client.ConnectionStatus().HighWatermark == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	server.CloseClientConnections()
+
+	assert.Equal(t, prefab.ConnectionReconnecting, nextState())
+
+	// a keep-alive on the new connection shows it's streaming again
+	sendUpdate(t, updates, &prefabProto.Configs{KeepAlive: proto.Bool(true)})
+
+	assert.Equal(t, prefab.ConnectionStreaming, nextState())
+	assert.Equal(t, int64(2), client.ConnectionStatus().HighWatermark)
+}
+
+func TestConnectionStatusWithoutAPI(t *testing.T) {
+	client, err := prefab.NewClient(
+		prefab.WithConfigs(map[string]interface{}{"foo": "bar"}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	assert.Equal(t, prefab.ConnectionLoaded, client.ConnectionStatus().State)
+}
+
+func TestMaxStaleness(t *testing.T) {
+	client, updates := newTestClientWithOptions(t,
+		[]*prefabProto.Config{stringConfig(t, "foo", 1, "one")},
+		prefab.WithMaxStaleness(100*time.Millisecond))
+
+	details, ok, err := client.GetStringDetails("foo", prefab.ContextSet{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.False(t, details.Stale)
+
+	// the fake API's stream stays quiet
+	assert.Eventually(t, func() bool { return client.ConnectionStatus().Stale }, 5*time.Second, 10*time.Millisecond)
+
+	details, ok, err = client.GetStringDetails("foo", prefab.ContextSet{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "one", details.Value)
+	assert.True(t, details.Stale)
+
+	sendUpdate(t, updates, &prefabProto.Configs{KeepAlive: proto.Bool(true)})
+
+	assert.Eventually(t, func() bool { return !client.ConnectionStatus().Stale }, 5*time.Second, 10*time.Millisecond)
+
+	_, err = prefab.NewClient(prefab.WithMaxStaleness(0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package prefab_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestCustomOperatorInDatafile(t *testing.T) {
+	const propInCIDR = prefabProto.Criterion_CriterionOperator(1001)
+
+	datafile := filepath.Join(t.TempDir(), "datafile.json")
+	require.NoError(t, os.WriteFile(datafile, []byte(`{
+		"configServicePointer": {"projectEnvId": "101"},
+		"configs": [{
+			"id": "1",
+			"key": "internal.network",
+			"configType": "FEATURE_FLAG",
+			"rows": [{"values": [
+				{"criteria": [{"propertyName": "request.ip", "operator": 1001, "valueToMatch": {"string": "10.0.0.0/8"}}], "value": {"bool": true}},
+				{"value": {"bool": false}}
+			]}]
+		}]
+	}`), 0o600))
+
+	inCIDR := func(criterion *prefabProto.Criterion, contextValue any, contextValueExists bool) bool {
+		_, network, err := net.ParseCIDR(criterion.GetValueToMatch().GetString_())
+		if err != nil || !contextValueExists {
+			return false
+		}
+
+		ip, isString := contextValue.(string)
+
+		return isString && network.Contains(net.ParseIP(ip))
+	}
+
+	client, err := prefab.NewClient(
+		prefab.WithOfflineSources([]string{"datafile://" + datafile}),
+		prefab.WithCustomOperator(propInCIDR, inCIDR),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	requestFrom := func(ip string) prefab.ContextSet {
+		return *prefab.NewContextSet().WithNamedContextValues("request", map[string]interface{}{"ip": ip})
+	}
+
+	enabled, ok, err := client.GetBoolValue("internal.network", requestFrom("10.1.2.3"))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, enabled)
+
+	enabled, _, err = client.GetBoolValue("internal.network", requestFrom("192.168.1.1"))
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	_, err = prefab.NewClient(
+		prefab.WithOfflineSources([]string{"datafile://" + datafile}),
+		prefab.WithCustomOperator(prefabProto.Criterion_PROP_IS_ONE_OF, inCIDR))
+	require.ErrorIs(t, err, prefab.ErrBuiltInOperator)
+}
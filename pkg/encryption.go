@@ -0,0 +1,20 @@
+package prefab
+
+import "github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+
+// GenerateSecretKey returns a new random hex-encoded key for EncryptValue.
+func GenerateSecretKey() (string, error) {
+	return internal.GenerateSecretKey()
+}
+
+// EncryptValue encrypts plaintext with the hex-encoded secretKey in the format
+// Prefab uses for confidential values. Store the result with a decrypt_with
+// naming the config (or SecretKeyProvider key) that holds secretKey.
+func EncryptValue(secretKey string, plaintext string) (string, error) {
+	return (&internal.Encryption{}).EncryptValue(secretKey, plaintext)
+}
+
+// DecryptValue decrypts a value encrypted by EncryptValue (or by Prefab).
+func DecryptValue(secretKey string, encryptedValue string) (string, error) {
+	return (&internal.Encryption{}).DecryptValue(secretKey, encryptedValue)
+}
@@ -0,0 +1,127 @@
+package prefab_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestEncryptValue(t *testing.T) {
+	secretKey, err := prefab.GenerateSecretKey()
+	require.NoError(t, err)
+
+	encryptedValue, err := prefab.EncryptValue(secretKey, "s3cret")
+	require.NoError(t, err)
+	assert.NotContains(t, encryptedValue, "s3cret")
+
+	decryptedValue, err := prefab.DecryptValue(secretKey, encryptedValue)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", decryptedValue)
+}
+
+func TestEncryptedValuesInDatafiles(t *testing.T) {
+	secretKey, err := prefab.GenerateSecretKey()
+	require.NoError(t, err)
+
+	encryptedValue, err := prefab.EncryptValue(secretKey, "hunter2")
+	require.NoError(t, err)
+
+	datafile := filepath.Join(t.TempDir(), "datafile.yaml")
+	require.NoError(t, os.WriteFile(datafile, []byte(fmt.Sprintf("db:\n  password: { value: %q, decrypt_with: \"secret.key\" }\n", encryptedValue)), 0o600))
+
+	client, err := prefab.NewClient(
+		prefab.WithOfflineSources([]string{"datafile://" + datafile}),
+		prefab.WithSecretKeyProvider(prefab.EnvSecretKeyProvider{
+			LookupEnv: func(string) (string, bool) { return secretKey, true },
+			EnvVars:   map[string][]string{"secret.key": {"SECRET_KEY"}},
+		}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	value, ok, err := client.GetStringValue("db.password", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "hunter2", value)
+}
+
+func TestDecryptionCyclesAreErrors(t *testing.T) {
+	datafile := filepath.Join(t.TempDir(), "datafile.yaml")
+	require.NoError(t, os.WriteFile(datafile, []byte(`self: { value: "abc--def--ghi", decrypt_with: "self" }
+ping: { value: "abc--def--ghi", decrypt_with: "pong" }
+pong: { value: "abc--def--ghi", decrypt_with: "ping" }
+`), 0o600))
+
+	client, err := prefab.NewClient(
+		prefab.WithOfflineSources([]string{"datafile://" + datafile}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	for _, key := range []string{"self", "ping", "pong"} {
+		_, _, err := client.GetStringValue(key, prefab.ContextSet{})
+		require.ErrorIs(t, err, prefab.ErrDecryptionCycle, key)
+	}
+}
+
+func TestDecryptionKeys(t *testing.T) {
+	const (
+		// #nosec G101 -- these are test keys
+		encryptedValue = "b837acfdedb9f6286947fb95f6fb--13490148d8d3ddf0decc3d14--add9b0ed6de775080bec4c5b6025d67e"
+		secretKey      = "e657e0406fc22e17d3145966396b2130d33dcb30ac0edd62a77235cdd01fc49d"
+		retiredKey     = "0000000000000000000000000000000000000000000000000000000000000000"
+	)
+
+	encryptedConfig := func(key string, id int64, decryptWith string) *prefabProto.Config {
+		return testConfig(key, id, prefabProto.ConfigType_CONFIG, always(&prefabProto.ConfigValue{
+			Type:        &prefabProto.ConfigValue_String_{String_: encryptedValue},
+			DecryptWith: internal.StringPtr(decryptWith),
+		}))
+	}
+
+	keyFromEnv := &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Provided{Provided: &prefabProto.Provided{
+		Source: prefabProto.ProvidedSource_ENV_VAR.Enum(),
+		Lookup: internal.StringPtr("SECRET_KEY"),
+	}}}
+
+	providerCalls := 0
+
+	client, _ := newTestClientWithOptions(t,
+		[]*prefabProto.Config{
+			testConfig("key.from.env", 1, prefabProto.ConfigType_CONFIG, always(keyFromEnv)),
+			testConfig("rotating.keys", 2, prefabProto.ConfigType_CONFIG, always(testutils.CreateConfigValueAndAssertOk(t, []string{retiredKey, secretKey}))),
+			encryptedConfig("secret.from.env", 3, "key.from.env"),
+			encryptedConfig("secret.with.rotating.keys", 4, "rotating.keys"),
+			encryptedConfig("secret.from.provider", 5, "kms"),
+		},
+		prefab.WithEnvLookup(mapEnvLookup{"SECRET_KEY": secretKey}),
+		prefab.WithSecretKeyProvider(prefab.SecretKeyProviderFunc(func(name string) ([]string, error) {
+			if name != "kms" {
+				return nil, prefab.ErrSecretKeyNotFound
+			}
+
+			providerCalls++
+
+			return []string{retiredKey, secretKey}, nil
+		})))
+
+	for _, key := range []string{"secret.from.env", "secret.with.rotating.keys", "secret.from.provider", "secret.from.provider"} {
+		value, ok, err := client.GetStringValue(key, prefab.ContextSet{})
+		require.NoError(t, err, key)
+		assert.True(t, ok, key)
+		assert.Equal(t, "james-was-here", value, key)
+	}
+
+	assert.Equal(t, 1, providerCalls, "decrypted values are cached")
+}
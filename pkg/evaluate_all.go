@@ -0,0 +1,200 @@
+package prefab
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/contexts"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/utils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// Evaluations holds the values of every client-visible config for one context,
+// e.g. to bootstrap a frontend SDK without another round trip.
+type Evaluations struct {
+	ContextSet  *ContextSet
+	Matches     map[string]ConfigMatch
+	EvaluatedAt time.Time
+	valueTypes  map[string]prefabProto.Config_ValueType
+}
+
+// EvaluateAll resolves every config marked SendToClientSdk for contextSet.
+// Configs that error, have no matching rule or resolve to a confidential value
+// are left out.
+func (c *Client) EvaluateAll(contextSet ContextSet) (*Evaluations, error) {
+	return c.boundClient.EvaluateAllCtx(context.Background(), contextSet)
+}
+
+// EvaluateAllCtx is like EvaluateAll but stops waiting for initialization when ctx is done.
+func (c *Client) EvaluateAllCtx(ctx context.Context, contextSet ContextSet) (*Evaluations, error) {
+	return c.boundClient.EvaluateAllCtx(ctx, contextSet)
+}
+
+// EvaluateAll is like Client.EvaluateAll, evaluating with the bound context merged in.
+func (c *ContextBoundClient) EvaluateAll(contextSet ContextSet) (*Evaluations, error) {
+	return c.EvaluateAllCtx(context.Background(), contextSet)
+}
+
+// EvaluateAllCtx is like Client.EvaluateAllCtx, evaluating with the bound context merged in.
+func (c *ContextBoundClient) EvaluateAllCtx(ctx context.Context, contextSet ContextSet) (*Evaluations, error) {
+	if err := c.client.awaitEvaluable(ctx); err != nil {
+		return nil, err
+	}
+
+	mergedContextSet := contexts.Merge(c.context, &contextSet)
+
+	c.client.telemetry.RecordContext(mergedContextSet)
+
+	evaluations := &Evaluations{
+		ContextSet:  mergedContextSet,
+		Matches:     make(map[string]ConfigMatch),
+		EvaluatedAt: time.Now(),
+		valueTypes:  make(map[string]prefabProto.Config_ValueType),
+	}
+
+	for _, key := range c.client.configResolver.Keys() {
+		config, exists := c.client.configStore.GetConfig(key)
+		if !exists || !config.GetSendToClientSdk() {
+			continue
+		}
+
+		match, err := c.client.configResolver.ResolveValueForConfig(config, mergedContextSet, key)
+		if err != nil {
+			slog.Debug("skipping config in EvaluateAll", "key", key, "err", err)
+
+			continue
+		}
+
+		if match.Match == nil || match.Match.GetConfidential() {
+			continue
+		}
+
+		evaluations.Matches[key] = match
+		evaluations.valueTypes[key] = config.GetValueType()
+	}
+
+	return evaluations, nil
+}
+
+// Keys returns the evaluated keys in sorted order.
+func (e *Evaluations) Keys() []string {
+	keys := make([]string, 0, len(e.Matches))
+	for key := range e.Matches {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ToEvaluatedConfigs returns the evaluations as an EvaluatedConfigs proto,
+// ordered by key.
+func (e *Evaluations) ToEvaluatedConfigs() *prefabProto.EvaluatedConfigs {
+	contextSet := e.ContextSet.ToProto()
+	evaluatedConfigs := &prefabProto.EvaluatedConfigs{}
+
+	for _, key := range e.Keys() {
+		match := e.Matches[key]
+
+		evaluatedConfigs.Configs = append(evaluatedConfigs.Configs, &prefabProto.EvaluatedConfig{
+			Key:           key,
+			ConfigVersion: match.ConfigID,
+			Result:        match.Match,
+			Context:       contextSet,
+			Timestamp:     e.EvaluatedAt.UnixMilli(),
+		})
+	}
+
+	return evaluatedConfigs
+}
+
+// ToConfigEvaluations returns the evaluations in the ConfigEvaluations format
+// served to client SDKs. Values that have no client representation (e.g.
+// bytes) are left out.
+func (e *Evaluations) ToConfigEvaluations() *prefabProto.ConfigEvaluations {
+	configEvaluations := &prefabProto.ConfigEvaluations{
+		Values:         make(map[string]*prefabProto.ClientConfigValue, len(e.Matches)),
+		DefaultContext: e.ContextSet.ToProto(),
+	}
+
+	for key, match := range e.Matches {
+		clientValue, ok := toClientConfigValue(match.Match)
+		if !ok {
+			slog.Debug("config value has no client representation", "key", key)
+
+			continue
+		}
+
+		clientValue.ConfigEvaluationMetadata = evaluationMetadata(match, e.valueTypes[key])
+		configEvaluations.Values[key] = clientValue
+	}
+
+	return configEvaluations
+}
+
+// MarshalJSON encodes the evaluations as the protobuf JSON form of ToConfigEvaluations.
+func (e *Evaluations) MarshalJSON() ([]byte, error) {
+	return protojson.Marshal(e.ToConfigEvaluations())
+}
+
+func toClientConfigValue(cv *prefabProto.ConfigValue) (*prefabProto.ClientConfigValue, bool) {
+	switch value := cv.GetType().(type) {
+	case *prefabProto.ConfigValue_Int:
+		return &prefabProto.ClientConfigValue{Type: &prefabProto.ClientConfigValue_Int{Int: value.Int}}, true
+	case *prefabProto.ConfigValue_String_:
+		return &prefabProto.ClientConfigValue{Type: &prefabProto.ClientConfigValue_String_{String_: value.String_}}, true
+	case *prefabProto.ConfigValue_Double:
+		return &prefabProto.ClientConfigValue{Type: &prefabProto.ClientConfigValue_Double{Double: value.Double}}, true
+	case *prefabProto.ConfigValue_Bool:
+		return &prefabProto.ClientConfigValue{Type: &prefabProto.ClientConfigValue_Bool{Bool: value.Bool}}, true
+	case *prefabProto.ConfigValue_LogLevel:
+		return &prefabProto.ClientConfigValue{Type: &prefabProto.ClientConfigValue_LogLevel{LogLevel: value.LogLevel}}, true
+	case *prefabProto.ConfigValue_StringList:
+		return &prefabProto.ClientConfigValue{Type: &prefabProto.ClientConfigValue_StringList{StringList: value.StringList}}, true
+	case *prefabProto.ConfigValue_IntRange:
+		return &prefabProto.ClientConfigValue{Type: &prefabProto.ClientConfigValue_IntRange{IntRange: value.IntRange}}, true
+	case *prefabProto.ConfigValue_Json:
+		return &prefabProto.ClientConfigValue{Type: &prefabProto.ClientConfigValue_Json{Json: value.Json}}, true
+	case *prefabProto.ConfigValue_Duration:
+		duration, ok := utils.ExtractDurationValue(cv)
+		if !ok {
+			return nil, false
+		}
+
+		return &prefabProto.ClientConfigValue{Type: &prefabProto.ClientConfigValue_Duration{Duration: &prefabProto.ClientDuration{
+			Seconds:    int64(duration / time.Second),
+			Nanos:      int32(duration % time.Second),
+			Definition: value.Duration.GetDefinition(),
+		}}}, true
+	default:
+		return nil, false
+	}
+}
+
+func evaluationMetadata(match ConfigMatch, valueType prefabProto.Config_ValueType) *prefabProto.ConfigEvaluationMetaData {
+	metadata := &prefabProto.ConfigEvaluationMetaData{
+		Type:      &match.ConfigType,
+		Id:        &match.ConfigID,
+		ValueType: &valueType,
+	}
+
+	if match.RowIndex != nil {
+		metadata.ConfigRowIndex = internal.Int64Ptr(int64(*match.RowIndex))
+	}
+
+	if match.ConditionalValueIndex != nil {
+		metadata.ConditionalValueIndex = internal.Int64Ptr(int64(*match.ConditionalValueIndex))
+	}
+
+	if match.WeightedValueIndex != nil {
+		metadata.WeightedValueIndex = internal.Int64Ptr(int64(*match.WeightedValueIndex))
+	}
+
+	return metadata
+}
@@ -0,0 +1,93 @@
+package prefab_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestEvaluateAll(t *testing.T) {
+	isPro := &prefabProto.Criterion{
+		PropertyName: "user.plan",
+		Operator:     prefabProto.Criterion_PROP_IS_ONE_OF,
+		ValueToMatch: testutils.CreateConfigValueAndAssertOk(t, []string{"pro"}),
+	}
+
+	confidential := testutils.CreateConfigValueAndAssertOk(t, "hunter2")
+	confidential.Confidential = internal.BoolPtr(true)
+
+	serverOnly := testConfig("server-only", 4, prefabProto.ConfigType_CONFIG, always(testutils.CreateConfigValueAndAssertOk(t, "secret")))
+
+	configs := []*prefabProto.Config{
+		testConfig("limit", 1, prefabProto.ConfigType_CONFIG,
+			&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{isPro}, Value: testutils.CreateConfigValueAndAssertOk(t, int64(100))},
+			always(testutils.CreateConfigValueAndAssertOk(t, int64(10)))),
+		testConfig("timeout", 2, prefabProto.ConfigType_CONFIG,
+			always(&prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Duration{Duration: &prefabProto.IsoDuration{Definition: "PT1.5S"}}})),
+		testConfig("pro-only", 3, prefabProto.ConfigType_CONFIG,
+			&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{isPro}, Value: testutils.CreateConfigValueAndAssertOk(t, true)}),
+		serverOnly,
+		testConfig("password", 5, prefabProto.ConfigType_CONFIG, always(confidential)),
+	}
+
+	for _, config := range configs {
+		config.SendToClientSdk = config != serverOnly
+	}
+
+	client, _ := newTestClient(t, configs...)
+
+	t.Run("free user", func(t *testing.T) {
+		evaluations, err := client.EvaluateAll(*prefab.NewContextSet().WithNamedContextValues("user", map[string]interface{}{"plan": "free"}))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"limit", "timeout"}, evaluations.Keys())
+
+		evaluatedConfigs := evaluations.ToEvaluatedConfigs()
+		require.Len(t, evaluatedConfigs.GetConfigs(), 2)
+		assert.Equal(t, "limit", evaluatedConfigs.GetConfigs()[0].GetKey())
+		assert.Equal(t, int64(1), evaluatedConfigs.GetConfigs()[0].GetConfigVersion())
+		assert.Equal(t, int64(10), evaluatedConfigs.GetConfigs()[0].GetResult().GetInt())
+		assert.Equal(t, "user", evaluatedConfigs.GetConfigs()[0].GetContext().GetContexts()[0].GetType())
+
+		configEvaluations := evaluations.ToConfigEvaluations()
+		assert.Equal(t, int64(10), configEvaluations.GetValues()["limit"].GetInt())
+		assert.Equal(t, int64(1), configEvaluations.GetValues()["timeout"].GetDuration().GetSeconds())
+		assert.Equal(t, int32(500_000_000), configEvaluations.GetValues()["timeout"].GetDuration().GetNanos())
+		assert.Equal(t, "PT1.5S", configEvaluations.GetValues()["timeout"].GetDuration().GetDefinition())
+
+		metadata := configEvaluations.GetValues()["limit"].GetConfigEvaluationMetadata()
+		assert.Equal(t, int64(1), metadata.GetId())
+		assert.Equal(t, int64(0), metadata.GetConfigRowIndex())
+		assert.Equal(t, int64(1), metadata.GetConditionalValueIndex())
+	})
+
+	t.Run("pro user", func(t *testing.T) {
+		evaluations, err := client.EvaluateAll(*prefab.NewContextSet().WithNamedContextValues("user", map[string]interface{}{"plan": "pro"}))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"limit", "pro-only", "timeout"}, evaluations.Keys())
+		assert.Equal(t, int64(100), evaluations.Matches["limit"].Match.GetInt())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		evaluations, err := client.EvaluateAll(prefab.ContextSet{})
+		require.NoError(t, err)
+
+		encoded, err := json.Marshal(evaluations)
+		require.NoError(t, err)
+
+		decoded := &prefabProto.ConfigEvaluations{}
+		require.NoError(t, protojson.Unmarshal(encoded, decoded))
+		assert.Equal(t, int64(10), decoded.GetValues()["limit"].GetInt())
+		assert.NotContains(t, decoded.GetValues(), "server-only")
+		assert.NotContains(t, decoded.GetValues(), "password")
+	})
+}
@@ -0,0 +1,235 @@
+package prefab
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/contexts"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/utils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// EvaluationReason explains where an evaluated value came from.
+type EvaluationReason = internal.EvaluationReason
+
+const (
+	// ReasonDefault means the value came from a conditional value without criteria
+	ReasonDefault = internal.ReasonDefault
+	// ReasonTargetingMatch means targeting criteria selected the value
+	ReasonTargetingMatch = internal.ReasonTargetingMatch
+	// ReasonSegmentMatch means a segment criterion selected the value
+	ReasonSegmentMatch = internal.ReasonSegmentMatch
+	// ReasonSplit means the value was chosen from weighted values
+	ReasonSplit = internal.ReasonSplit
+	// ReasonProvided means the value was read from an environment variable
+	ReasonProvided = internal.ReasonProvided
+	// ReasonDecrypted means the value was decrypted
+	ReasonDecrypted = internal.ReasonDecrypted
+	// ReasonNoMatch means no rule matched the context
+	ReasonNoMatch = internal.ReasonNoMatch
+	// ReasonError means the config was missing, couldn't be evaluated or had the wrong type
+	ReasonError = internal.ReasonError
+	// ReasonDisallowedValue means the value wasn't one of the config's allowable values, so the first allowable value was used (see UseFirstAllowedValue)
+	ReasonDisallowedValue = internal.ReasonDisallowedValue
+)
+
+// EvaluationDetails is an evaluated value along with how it was chosen.
+// MatchedCriteria holds the criteria of the conditional value that matched
+// (empty for defaults). ConfigID identifies the version of the config used.
+// Stale is set if the configs were stale (see WithMaxStaleness) at the time.
+type EvaluationDetails[T any] struct {
+	Value                 T
+	MatchedCriteria       []*prefabProto.Criterion
+	RowIndex              *int
+	ConditionalValueIndex *int
+	WeightedValueIndex    *int
+	Key                   string
+	ConfigID              int64
+	ConfigType            prefabProto.ConfigType
+	Reason                EvaluationReason
+	Stale                 bool
+}
+
+// GetIntDetails returns an int value for a given key and context along with evaluation details
+func (c *Client) GetIntDetails(key string, contextSet ContextSet) (EvaluationDetails[int64], bool, error) {
+	return c.boundClient.GetIntDetails(key, contextSet)
+}
+
+// GetIntDetailsCtx is like GetIntDetails but stops waiting for initialization when ctx is done.
+func (c *Client) GetIntDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[int64], bool, error) {
+	return c.boundClient.GetIntDetailsCtx(ctx, key, contextSet)
+}
+
+// GetStringDetails returns a string value for a given key and context along with evaluation details
+func (c *Client) GetStringDetails(key string, contextSet ContextSet) (EvaluationDetails[string], bool, error) {
+	return c.boundClient.GetStringDetails(key, contextSet)
+}
+
+// GetStringDetailsCtx is like GetStringDetails but stops waiting for initialization when ctx is done.
+func (c *Client) GetStringDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[string], bool, error) {
+	return c.boundClient.GetStringDetailsCtx(ctx, key, contextSet)
+}
+
+// GetBoolDetails returns a bool value for a given key and context along with evaluation details
+func (c *Client) GetBoolDetails(key string, contextSet ContextSet) (EvaluationDetails[bool], bool, error) {
+	return c.boundClient.GetBoolDetails(key, contextSet)
+}
+
+// GetBoolDetailsCtx is like GetBoolDetails but stops waiting for initialization when ctx is done.
+func (c *Client) GetBoolDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[bool], bool, error) {
+	return c.boundClient.GetBoolDetailsCtx(ctx, key, contextSet)
+}
+
+// GetFloatDetails returns a float value for a given key and context along with evaluation details
+func (c *Client) GetFloatDetails(key string, contextSet ContextSet) (EvaluationDetails[float64], bool, error) {
+	return c.boundClient.GetFloatDetails(key, contextSet)
+}
+
+// GetFloatDetailsCtx is like GetFloatDetails but stops waiting for initialization when ctx is done.
+func (c *Client) GetFloatDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[float64], bool, error) {
+	return c.boundClient.GetFloatDetailsCtx(ctx, key, contextSet)
+}
+
+// GetStringSliceDetails returns a string slice value for a given key and context along with evaluation details
+func (c *Client) GetStringSliceDetails(key string, contextSet ContextSet) (EvaluationDetails[[]string], bool, error) {
+	return c.boundClient.GetStringSliceDetails(key, contextSet)
+}
+
+// GetStringSliceDetailsCtx is like GetStringSliceDetails but stops waiting for initialization when ctx is done.
+func (c *Client) GetStringSliceDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[[]string], bool, error) {
+	return c.boundClient.GetStringSliceDetailsCtx(ctx, key, contextSet)
+}
+
+// GetDurationDetails returns a duration value for a given key and context along with evaluation details
+func (c *Client) GetDurationDetails(key string, contextSet ContextSet) (EvaluationDetails[time.Duration], bool, error) {
+	return c.boundClient.GetDurationDetails(key, contextSet)
+}
+
+// GetDurationDetailsCtx is like GetDurationDetails but stops waiting for initialization when ctx is done.
+func (c *Client) GetDurationDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[time.Duration], bool, error) {
+	return c.boundClient.GetDurationDetailsCtx(ctx, key, contextSet)
+}
+
+// GetJSONDetails returns a JSON value for a given key and context along with evaluation details
+func (c *Client) GetJSONDetails(key string, contextSet ContextSet) (EvaluationDetails[interface{}], bool, error) {
+	return c.boundClient.GetJSONDetails(key, contextSet)
+}
+
+// GetJSONDetailsCtx is like GetJSONDetails but stops waiting for initialization when ctx is done.
+func (c *Client) GetJSONDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[interface{}], bool, error) {
+	return c.boundClient.GetJSONDetailsCtx(ctx, key, contextSet)
+}
+
+// GetIntDetails returns an int value for a given key and context along with evaluation details
+func (c *ContextBoundClient) GetIntDetails(key string, contextSet ContextSet) (EvaluationDetails[int64], bool, error) {
+	return c.GetIntDetailsCtx(context.Background(), key, contextSet)
+}
+
+// GetIntDetailsCtx is like GetIntDetails but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetIntDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[int64], bool, error) {
+	return clientInternalGetDetailsFunc(ctx, c, key, contextSet, utils.ExtractIntValue)
+}
+
+// GetStringDetails returns a string value for a given key and context along with evaluation details
+func (c *ContextBoundClient) GetStringDetails(key string, contextSet ContextSet) (EvaluationDetails[string], bool, error) {
+	return c.GetStringDetailsCtx(context.Background(), key, contextSet)
+}
+
+// GetStringDetailsCtx is like GetStringDetails but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetStringDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[string], bool, error) {
+	return clientInternalGetDetailsFunc(ctx, c, key, contextSet, utils.ExtractStringValue)
+}
+
+// GetBoolDetails returns a bool value for a given key and context along with evaluation details
+func (c *ContextBoundClient) GetBoolDetails(key string, contextSet ContextSet) (EvaluationDetails[bool], bool, error) {
+	return c.GetBoolDetailsCtx(context.Background(), key, contextSet)
+}
+
+// GetBoolDetailsCtx is like GetBoolDetails but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetBoolDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[bool], bool, error) {
+	return clientInternalGetDetailsFunc(ctx, c, key, contextSet, utils.ExtractBoolValue)
+}
+
+// GetFloatDetails returns a float value for a given key and context along with evaluation details
+func (c *ContextBoundClient) GetFloatDetails(key string, contextSet ContextSet) (EvaluationDetails[float64], bool, error) {
+	return c.GetFloatDetailsCtx(context.Background(), key, contextSet)
+}
+
+// GetFloatDetailsCtx is like GetFloatDetails but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetFloatDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[float64], bool, error) {
+	return clientInternalGetDetailsFunc(ctx, c, key, contextSet, utils.ExtractFloatValue)
+}
+
+// GetStringSliceDetails returns a string slice value for a given key and context along with evaluation details
+func (c *ContextBoundClient) GetStringSliceDetails(key string, contextSet ContextSet) (EvaluationDetails[[]string], bool, error) {
+	return c.GetStringSliceDetailsCtx(context.Background(), key, contextSet)
+}
+
+// GetStringSliceDetailsCtx is like GetStringSliceDetails but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetStringSliceDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[[]string], bool, error) {
+	return clientInternalGetDetailsFunc(ctx, c, key, contextSet, utils.ExtractStringListValue)
+}
+
+// GetDurationDetails returns a duration value for a given key and context along with evaluation details
+func (c *ContextBoundClient) GetDurationDetails(key string, contextSet ContextSet) (EvaluationDetails[time.Duration], bool, error) {
+	return c.GetDurationDetailsCtx(context.Background(), key, contextSet)
+}
+
+// GetDurationDetailsCtx is like GetDurationDetails but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetDurationDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[time.Duration], bool, error) {
+	return clientInternalGetDetailsFunc(ctx, c, key, contextSet, utils.ExtractDurationValue)
+}
+
+// GetJSONDetails returns a JSON value for a given key and context along with evaluation details
+func (c *ContextBoundClient) GetJSONDetails(key string, contextSet ContextSet) (EvaluationDetails[interface{}], bool, error) {
+	return c.GetJSONDetailsCtx(context.Background(), key, contextSet)
+}
+
+// GetJSONDetailsCtx is like GetJSONDetails but stops waiting for initialization when ctx is done.
+func (c *ContextBoundClient) GetJSONDetailsCtx(ctx context.Context, key string, contextSet ContextSet) (EvaluationDetails[interface{}], bool, error) {
+	return clientInternalGetDetailsFunc(ctx, c, key, contextSet, utils.ExtractJSONValueWithoutError)
+}
+
+func clientInternalGetDetailsFunc[T any](ctx context.Context, contextBoundClient *ContextBoundClient, key string, contextSet contexts.ContextSet, parseFunc func(*prefabProto.ConfigValue) (T, bool)) (EvaluationDetails[T], bool, error) {
+	mergedContextSet := *contexts.Merge(contextBoundClient.context, &contextSet)
+
+	contextBoundClient.client.telemetry.RecordContext(&mergedContextSet)
+
+	result, err := contextBoundClient.client.internalGetValue(ctx, key, mergedContextSet)
+	match := result.match
+
+	details := EvaluationDetails[T]{
+		Key:                   key,
+		ConfigID:              match.ConfigID,
+		ConfigType:            match.ConfigType,
+		Reason:                match.Reason,
+		MatchedCriteria:       match.MatchedCriteria,
+		RowIndex:              match.RowIndex,
+		ConditionalValueIndex: match.ConditionalValueIndex,
+		WeightedValueIndex:    match.WeightedValueIndex,
+		Stale:                 contextBoundClient.client.connection.Status().Stale,
+	}
+
+	if err != nil {
+		details.Reason = ReasonError
+
+		return details, false, err
+	}
+
+	if match.Match == nil {
+		return details, false, errors.New("config did not produce a result and no default is specified")
+	}
+
+	value, ok := parseFunc(match.Match)
+	if !ok {
+		details.Reason = ReasonError
+
+		return details, false, nil
+	}
+
+	details.Value = value
+
+	return details, true, nil
+}
@@ -0,0 +1,118 @@
+package prefab_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestEvaluationDetails(t *testing.T) {
+	isPro := &prefabProto.Criterion{
+		PropertyName: "user.plan",
+		Operator:     prefabProto.Criterion_PROP_IS_ONE_OF,
+		ValueToMatch: testutils.CreateConfigValueAndAssertOk(t, []string{"pro"}),
+	}
+	inProSegment := &prefabProto.Criterion{
+		Operator:     prefabProto.Criterion_IN_SEG,
+		ValueToMatch: testutils.CreateConfigValueAndAssertOk(t, "pro-users"),
+	}
+	isBeta := &prefabProto.Criterion{
+		PropertyName: "user.beta",
+		Operator:     prefabProto.Criterion_PROP_IS_ONE_OF,
+		ValueToMatch: testutils.CreateConfigValueAndAssertOk(t, []string{"true"}),
+	}
+
+	segment := testConfig("pro-users", 1, prefabProto.ConfigType_SEGMENT,
+		&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{isPro}, Value: testutils.CreateConfigValueAndAssertOk(t, true)},
+		always(testutils.CreateConfigValueAndAssertOk(t, false)))
+
+	split := &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_WeightedValues{WeightedValues: &prefabProto.WeightedValues{
+		HashByPropertyName: internal.StringPtr("user.key"),
+		WeightedValues:     []*prefabProto.WeightedValue{{Weight: 100, Value: testutils.CreateConfigValueAndAssertOk(t, int64(7))}},
+	}}}
+
+	configs := []*prefabProto.Config{
+		segment,
+		testConfig("limit", 2, prefabProto.ConfigType_CONFIG,
+			&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{isBeta}, Value: testutils.CreateConfigValueAndAssertOk(t, int64(100))},
+			&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{inProSegment}, Value: testutils.CreateConfigValueAndAssertOk(t, int64(50))},
+			&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{{Operator: prefabProto.Criterion_ALWAYS_TRUE}}, Value: split}),
+		testConfig("beta-only", 3, prefabProto.ConfigType_CONFIG,
+			&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{isBeta}, Value: testutils.CreateConfigValueAndAssertOk(t, int64(1))}),
+	}
+
+	client, _ := newTestClient(t, configs...)
+
+	user := func(values map[string]interface{}) prefab.ContextSet {
+		return *prefab.NewContextSet().WithNamedContextValues("user", values)
+	}
+
+	tests := []struct {
+		name            string
+		key             string
+		contextSet      prefab.ContextSet
+		expectedValue   int64
+		expectedReason  prefab.EvaluationReason
+		expectedOk      bool
+		expectError     bool
+		matchedCriteria []*prefabProto.Criterion
+	}{
+		{"targeting", "limit", user(map[string]interface{}{"beta": "true"}), 100, prefab.ReasonTargetingMatch, true, false, []*prefabProto.Criterion{isBeta}},
+		{"segment", "limit", user(map[string]interface{}{"plan": "pro"}), 50, prefab.ReasonSegmentMatch, true, false, []*prefabProto.Criterion{inProSegment}},
+		{"split", "limit", user(map[string]interface{}{"key": "abc"}), 7, prefab.ReasonSplit, true, false, nil},
+		{"no match", "beta-only", user(map[string]interface{}{}), 0, prefab.ReasonNoMatch, false, true, nil},
+		{"missing", "does.not.exist", user(map[string]interface{}{}), 0, prefab.ReasonError, false, true, nil},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			details, ok, err := client.GetIntDetails(testCase.key, testCase.contextSet)
+			if testCase.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, testCase.expectedOk, ok)
+			assert.Equal(t, testCase.expectedValue, details.Value)
+			assert.Equal(t, testCase.expectedReason, details.Reason)
+			assert.Equal(t, testCase.key, details.Key)
+
+			if testCase.matchedCriteria != nil {
+				require.Len(t, details.MatchedCriteria, len(testCase.matchedCriteria))
+
+				for i, criterion := range testCase.matchedCriteria {
+					assert.True(t, proto.Equal(criterion, details.MatchedCriteria[i]))
+				}
+			}
+		})
+	}
+
+	details, ok, err := client.GetStringDetails("limit", user(map[string]interface{}{"beta": "true"}))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, prefab.ReasonError, details.Reason)
+	assert.Equal(t, int64(2), details.ConfigID)
+}
+
+func TestEvaluationDetailsDefault(t *testing.T) {
+	client, err := prefab.NewClient(
+		prefab.WithConfigs(map[string]interface{}{"string.key": "value"}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	details, ok, err := client.GetStringDetails("string.key", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "value", details.Value)
+	assert.Equal(t, prefab.ReasonDefault, details.Reason)
+	assert.Equal(t, "DEFAULT", details.Reason.String())
+	assert.Empty(t, details.MatchedCriteria)
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// ErrValueNotAllowed is matched (via errors.Is) by *ValueNotAllowedError.
+var ErrValueNotAllowed = errors.New("value is not one of the config's allowable values")
+
+// ValueNotAllowedError reports that Key resolved to (or was configured with)
+// Value, which isn't in the config's AllowableValues.
+type ValueNotAllowedError struct {
+	Value *prefabProto.ConfigValue
+	Key   string
+}
+
+func (e *ValueNotAllowedError) Error() string {
+	return fmt.Sprintf("config %q has value %s which is not one of its allowable values", e.Key, e.Value.String())
+}
+
+func (e *ValueNotAllowedError) Is(target error) bool {
+	return target == ErrValueNotAllowed
+}
+
+// AllowableValuesSupplier is implemented by stores that can find a key's
+// AllowableValues even when the config they return for it (e.g. a local
+// override) doesn't carry them.
+type AllowableValuesSupplier interface {
+	GetAllowableValues(key string) []*prefabProto.ConfigValue
+}
+
+// IsAllowedValue reports whether value is in allowed. Values are compared by
+// type and content only, so flags like Confidential don't matter. Every value
+// is allowed when allowed is empty.
+func IsAllowedValue(allowed []*prefabProto.ConfigValue, value *prefabProto.ConfigValue) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, allowedValue := range allowed {
+		if proto.Equal(&prefabProto.ConfigValue{Type: allowedValue.GetType()}, &prefabProto.ConfigValue{Type: value.GetType()}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckAllowableValues returns a *ValueNotAllowedError for the first literal
+// value in config that isn't allowed. Values only known at evaluation time
+// (environment variables and encrypted values) are checked when resolved.
+func CheckAllowableValues(config *prefabProto.Config) error {
+	allowed := config.GetAllowableValues()
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	for _, row := range config.GetRows() {
+		for _, conditionalValue := range row.GetValues() {
+			values := []*prefabProto.ConfigValue{conditionalValue.GetValue()}
+			for _, weightedValue := range conditionalValue.GetValue().GetWeightedValues().GetWeightedValues() {
+				values = append(values, weightedValue.GetValue())
+			}
+
+			for _, value := range values {
+				switch {
+				case value.GetWeightedValues() != nil, value.GetProvided() != nil, value.GetDecryptWith() != "":
+					continue
+				case !IsAllowedValue(allowed, value):
+					return &ValueNotAllowedError{Key: config.GetKey(), Value: value}
+				}
+			}
+		}
+	}
+
+	return nil
+}
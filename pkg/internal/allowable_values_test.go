@@ -0,0 +1,65 @@
+package internal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestIsAllowedValue(t *testing.T) {
+	allowed := []*prefabProto.ConfigValue{
+		testutils.CreateConfigValueAndAssertOk(t, "red"),
+		testutils.CreateConfigValueAndAssertOk(t, int64(1)),
+	}
+
+	confidentialRed := testutils.CreateConfigValueAndAssertOk(t, "red")
+	confidentialRed.Confidential = internal.BoolPtr(true)
+
+	assert.True(t, internal.IsAllowedValue(allowed, testutils.CreateConfigValueAndAssertOk(t, "red")))
+	assert.True(t, internal.IsAllowedValue(allowed, testutils.CreateConfigValueAndAssertOk(t, int64(1))))
+	assert.True(t, internal.IsAllowedValue(allowed, confidentialRed), "flags don't matter")
+	assert.False(t, internal.IsAllowedValue(allowed, testutils.CreateConfigValueAndAssertOk(t, "1")))
+	assert.False(t, internal.IsAllowedValue(allowed, testutils.CreateConfigValueAndAssertOk(t, "green")))
+	assert.True(t, internal.IsAllowedValue(nil, testutils.CreateConfigValueAndAssertOk(t, "green")), "anything goes without allowable values")
+}
+
+func TestCheckAllowableValues(t *testing.T) {
+	provided := &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Provided{Provided: &prefabProto.Provided{
+		Source: prefabProto.ProvidedSource_ENV_VAR.Enum(),
+		Lookup: internal.StringPtr("COLOR"),
+	}}}
+
+	config := &prefabProto.Config{
+		Key:             "color",
+		AllowableValues: []*prefabProto.ConfigValue{testutils.CreateConfigValueAndAssertOk(t, "red"), testutils.CreateConfigValueAndAssertOk(t, "blue")},
+		Rows: []*prefabProto.ConfigRow{{Values: []*prefabProto.ConditionalValue{
+			{Value: testutils.CreateConfigValueAndAssertOk(t, "red")},
+			{Value: provided},
+			{Value: &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_WeightedValues{WeightedValues: &prefabProto.WeightedValues{
+				WeightedValues: []*prefabProto.WeightedValue{
+					{Weight: 1, Value: testutils.CreateConfigValueAndAssertOk(t, "blue")},
+				},
+			}}}},
+		}}},
+	}
+
+	require.NoError(t, internal.CheckAllowableValues(config), "provided values are checked when resolved")
+
+	weighted := config.GetRows()[0].GetValues()[2].GetValue().GetWeightedValues()
+	weighted.WeightedValues = append(weighted.WeightedValues, &prefabProto.WeightedValue{Weight: 1, Value: testutils.CreateConfigValueAndAssertOk(t, "green")})
+
+	err := internal.CheckAllowableValues(config)
+	require.ErrorIs(t, err, internal.ErrValueNotAllowed)
+
+	var notAllowedErr *internal.ValueNotAllowedError
+
+	require.True(t, errors.As(err, &notAllowedErr))
+	assert.Equal(t, "color", notAllowedErr.Key)
+	assert.Equal(t, "green", notAllowedErr.Value.GetString_())
+}
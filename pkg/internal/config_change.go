@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// ChangeType describes what happened to a config in a ChangeEvent.
+type ChangeType int
+
+const (
+	// ChangeTypeAdded means a key that wasn't in the store before now is.
+	ChangeTypeAdded ChangeType = iota
+	// ChangeTypeUpdated means a newer version of an existing config replaced the old one.
+	ChangeTypeUpdated
+	// ChangeTypeDeleted means the config was tombstoned and removed from the store.
+	ChangeTypeDeleted
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeTypeAdded:
+		return "added"
+	case ChangeTypeUpdated:
+		return "updated"
+	case ChangeTypeDeleted:
+		return "deleted"
+	default:
+		return fmt.Sprintf("ChangeType(%d)", int(t))
+	}
+}
+
+// ChangeEvent describes a single config being added, updated or deleted in a
+// config store. Old is nil for additions and New is nil for deletions.
+// ConfigID is the ID of the config (or tombstone) that caused the change.
+type ChangeEvent struct {
+	Old      *prefabProto.Config
+	New      *prefabProto.Config
+	Key      string
+	ConfigID int64
+	Type     ChangeType
+}
+
+// ConfigChangeNotifier fans ChangeEvents out to registered listeners. A nil
+// *ConfigChangeNotifier is valid and drops every event.
+type ConfigChangeNotifier struct {
+	listeners      map[int]func(ChangeEvent)
+	batchListeners map[int]func([]ChangeEvent)
+	nextID         int
+	mutex          sync.RWMutex
+}
+
+func NewConfigChangeNotifier() *ConfigChangeNotifier {
+	return &ConfigChangeNotifier{
+		listeners:      make(map[int]func(ChangeEvent)),
+		batchListeners: make(map[int]func([]ChangeEvent)),
+	}
+}
+
+// Subscribe registers listener and returns a function that unregisters it.
+func (n *ConfigChangeNotifier) Subscribe(listener func(ChangeEvent)) (unsubscribe func()) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	id := n.nextID
+	n.nextID++
+	n.listeners[id] = listener
+
+	return func() {
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+
+		delete(n.listeners, id)
+	}
+}
+
+// SubscribeBatch registers listener to be called once per store update with
+// all of the update's events, and returns a function that unregisters it.
+func (n *ConfigChangeNotifier) SubscribeBatch(listener func([]ChangeEvent)) (unsubscribe func()) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	id := n.nextID
+	n.nextID++
+	n.batchListeners[id] = listener
+
+	return func() {
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+
+		delete(n.batchListeners, id)
+	}
+}
+
+// Notify calls every listener with each event, in order, then every batch
+// listener with all of them. Listeners are called synchronously on the
+// caller's goroutine (usually the one applying SSE updates), so they should
+// return quickly.
+func (n *ConfigChangeNotifier) Notify(events []ChangeEvent) {
+	if n == nil || len(events) == 0 {
+		return
+	}
+
+	n.mutex.RLock()
+
+	listeners := make([]func(ChangeEvent), 0, len(n.listeners))
+	for _, listener := range n.listeners {
+		listeners = append(listeners, listener)
+	}
+
+	batchListeners := make([]func([]ChangeEvent), 0, len(n.batchListeners))
+	for _, listener := range n.batchListeners {
+		batchListeners = append(batchListeners, listener)
+	}
+
+	n.mutex.RUnlock()
+
+	for _, event := range events {
+		for _, listener := range listeners {
+			callListener(listener, event)
+		}
+	}
+
+	for _, listener := range batchListeners {
+		callBatchListener(listener, events)
+	}
+}
+
+func callListener(listener func(ChangeEvent), event ChangeEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("config change listener panicked", "key", event.Key, "panic", r)
+		}
+	}()
+
+	listener(event)
+}
+
+func callBatchListener(listener func([]ChangeEvent), events []ChangeEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("config change listener panicked", "events", len(events), "panic", r)
+		}
+	}()
+
+	listener(events)
+}
@@ -0,0 +1,34 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+)
+
+func TestConfigChangeNotifierBatches(t *testing.T) {
+	notifier := internal.NewConfigChangeNotifier()
+
+	var (
+		keys    []string
+		batches [][]internal.ChangeEvent
+	)
+
+	notifier.Subscribe(func(event internal.ChangeEvent) { keys = append(keys, event.Key) })
+	unsubscribe := notifier.SubscribeBatch(func(events []internal.ChangeEvent) { batches = append(batches, events) })
+
+	notifier.Notify([]internal.ChangeEvent{{Key: "a"}, {Key: "b"}})
+	notifier.Notify(nil)
+
+	assert.Equal(t, []string{"a", "b"}, keys)
+	assert.Len(t, batches, 1)
+	assert.Len(t, batches[0], 2)
+
+	unsubscribe()
+	notifier.Notify([]internal.ChangeEvent{{Key: "c"}})
+
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+	assert.Len(t, batches, 1)
+}
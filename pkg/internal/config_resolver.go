@@ -2,10 +2,13 @@ package internal
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"time"
 
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/secrets"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/utils"
 	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
@@ -19,16 +22,25 @@ type ConfigMatch struct {
 	WeightedValueIndex    *int
 	RowIndex              *int
 	ConditionalValueIndex *int
+	MatchedCriteria       []*prefabProto.Criterion
+	Reason                EvaluationReason
 	IsMatch               bool
 }
 
 func NewConfigMatchFromConditionMatch(conditionMatch ConditionMatch) ConfigMatch {
+	reason := ReasonNoMatch
+	if conditionMatch.IsMatch {
+		reason = reasonForCriteria(conditionMatch.MatchedCriteria)
+	}
+
 	return ConfigMatch{
 		IsMatch:               conditionMatch.IsMatch,
 		OriginalMatch:         conditionMatch.Match,
 		Match:                 conditionMatch.Match,
 		RowIndex:              conditionMatch.RowIndex,
 		ConditionalValueIndex: conditionMatch.ConditionalValueIndex,
+		MatchedCriteria:       conditionMatch.MatchedCriteria,
+		Reason:                reason,
 	}
 }
 
@@ -40,8 +52,13 @@ func (RealEnvLookup) LookupEnv(key string) (string, bool) {
 
 var (
 	ErrConfigDoesNotExist = errors.New("config does not exist")
-	ErrEnvVarNotExist     = errors.New("environment variable does not exist")
-	ErrTypeCoercionFailed = errors.New("type coercion failed on value from environment variable")
+	// ErrProvidedValueNotFound means the ProvidedResolver has no value for a
+	// Provided config value.
+	ErrProvidedValueNotFound = errors.New("provided value not found")
+	ErrTypeCoercionFailed    = errors.New("type coercion failed on provided value")
+	// ErrDecryptionCycle means a config's decrypt_with key is, directly or
+	// through other keys, decrypted with the config itself.
+	ErrDecryptionCycle = errors.New("decryption keys refer to each other in a loop")
 )
 
 type ConfigResolver struct {
@@ -51,6 +68,18 @@ type ConfigResolver struct {
 	Decrypter             Decrypter
 	EnvLookup             EnvLookup
 	ContextGetter         ContextValueGetter
+	SchemaValidator       *SchemaValidator
+	// ProvidedResolver resolves Provided values. If it is nil, ENV_VAR values
+	// are looked up with EnvLookup.
+	ProvidedResolver utils.ProvidedResolver
+	// SecretKeyProvider, if set, is asked for decryption keys before the
+	// decrypt_with config is evaluated.
+	SecretKeyProvider secrets.SecretKeyProvider
+	// DecryptionCache, if set, saves decrypting the same value repeatedly.
+	DecryptionCache *DecryptionCache
+	// UseFirstAllowedValue replaces a value that isn't in the config's
+	// AllowableValues with the first allowable value instead of failing.
+	UseFirstAllowedValue bool
 }
 
 func NewConfigResolver(configStore ConfigStoreGetter, envLookup EnvLookup) *ConfigResolver {
@@ -64,6 +93,8 @@ func NewConfigResolver(configStore ConfigStoreGetter, envLookup EnvLookup) *Conf
 		Decrypter:             &Encryption{},
 		EnvLookup:             envLookup,
 		ContextGetter:         configStore,
+		SchemaValidator:       NewSchemaValidator(),
+		DecryptionCache:       NewDecryptionCache(),
 	}
 }
 
@@ -74,13 +105,19 @@ func (c ConfigResolver) Keys() []string {
 func (c ConfigResolver) ResolveValue(key string, contextSet ContextValueGetter) (ConfigMatch, error) {
 	config, configExists := c.ConfigStore.GetConfig(key)
 	if !configExists {
-		return ConfigMatch{IsMatch: false, ConfigKey: key}, ErrConfigDoesNotExist
+		return ConfigMatch{IsMatch: false, ConfigKey: key, Reason: ReasonError}, ErrConfigDoesNotExist
 	}
 
 	return c.ResolveValueForConfig(config, contextSet, key)
 }
 
 func (c ConfigResolver) ResolveValueForConfig(config *prefabProto.Config, contextSet ContextValueGetter, key string) (ConfigMatch, error) {
+	return c.resolveValueForConfig(config, contextSet, key, nil)
+}
+
+// resolveValueForConfig is ResolveValueForConfig while decrypting the configs
+// with the keys in decrypting, each of which is decrypted with the next one.
+func (c ConfigResolver) resolveValueForConfig(config *prefabProto.Config, contextSet ContextValueGetter, key string, decrypting []string) (ConfigMatch, error) {
 	contextSet = makeMultiContextGetter(contextSet, c.ContextGetter)
 
 	ruleMatchResults := c.RuleEvaluator.EvaluateConfig(config, contextSet)
@@ -89,57 +126,145 @@ func (c ConfigResolver) ResolveValueForConfig(config *prefabProto.Config, contex
 	configMatch.ConfigType = config.GetConfigType()
 	configMatch.ConfigID = config.GetId()
 
-	switch v := ruleMatchResults.Match.GetType().(type) {
-	case *prefabProto.ConfigValue_WeightedValues:
-		result, index := c.handleWeightedValue(key, v.WeightedValues, contextSet)
+	if weightedValues := ruleMatchResults.Match.GetWeightedValues(); weightedValues != nil {
+		result, index := c.handleWeightedValue(key, weightedValues, contextSet)
 		configMatch.WeightedValueIndex = &index
 		configMatch.Match = result
+		configMatch.Reason = ReasonSplit
+	}
+
+	// the value picked by a split may itself be provided or encrypted, in
+	// which case the reason stays ReasonSplit
+	switch configMatch.Match.GetType().(type) {
 	case *prefabProto.ConfigValue_Provided:
-		provided := ruleMatchResults.Match.GetProvided()
+		provided := configMatch.Match.GetProvided()
 		if provided != nil {
 			envValue, envValueExists := c.handleProvided(provided)
 			if envValueExists {
 				if coercedValue, coercionWorked := coerceValue(envValue, config.GetValueType()); coercionWorked {
 					newValue, _ := utils.Create(coercedValue)
 					configMatch.Match = newValue
+					configMatch.Reason = resolvedReason(configMatch, ReasonProvided)
 				} else {
+					configMatch.Reason = ReasonError
+
 					return configMatch, ErrTypeCoercionFailed
 				}
 			} else {
-				return configMatch, ErrEnvVarNotExist
+				configMatch.Reason = ReasonError
+
+				return configMatch, ErrProvidedValueNotFound
 			}
 		}
 	case *prefabProto.ConfigValue_String_:
-		if ruleMatchResults.Match.GetDecryptWith() != "" {
-			decryptedValue, err := c.handleDecryption(ruleMatchResults.Match, contextSet)
+		if configMatch.Match.GetDecryptWith() != "" {
+			decryptedValue, err := c.handleDecryption(config, configMatch.Match, contextSet, decrypting)
 			if err == nil {
 				value, _ := utils.Create(decryptedValue)
 				configMatch.Match = value
 				configMatch.Match.Confidential = BoolPtr(true)
+				configMatch.Reason = resolvedReason(configMatch, ReasonDecrypted)
 			} else {
+				configMatch.Reason = ReasonError
+
 				return configMatch, err
 			}
 		}
 	}
 
 	originalMatchIsConfidential := configMatch.OriginalMatch.GetConfidential()
-	if configMatch.OriginalMatch != configMatch.Match && ruleMatchResults.Match.GetDecryptWith() == "" && originalMatchIsConfidential {
+	if configMatch.OriginalMatch != configMatch.Match && !configMatch.Match.GetConfidential() && originalMatchIsConfidential {
 		configMatch.Match.Confidential = BoolPtr(configMatch.OriginalMatch.GetConfidential())
 	}
 
+	if err := c.validateSchema(config, configMatch.Match, contextSet); err != nil {
+		configMatch.Reason = ReasonError
+
+		return configMatch, err
+	}
+
+	if allowed := c.allowableValues(config, key); configMatch.Match != nil && !IsAllowedValue(allowed, configMatch.Match) {
+		if !c.UseFirstAllowedValue {
+			configMatch.Reason = ReasonError
+
+			return configMatch, &ValueNotAllowedError{Key: key, Value: configMatch.Match}
+		}
+
+		configMatch.Match = allowed[0]
+		configMatch.Reason = ReasonDisallowedValue
+	}
+
 	return configMatch, nil
 }
 
+// AllowableValues returns the values key is allowed to resolve to, which is
+// empty when any value is allowed.
+func (c ConfigResolver) AllowableValues(key string) ([]*prefabProto.ConfigValue, error) {
+	config, configExists := c.ConfigStore.GetConfig(key)
+	if !configExists {
+		return nil, ErrConfigDoesNotExist
+	}
+
+	return c.allowableValues(config, key), nil
+}
+
+// allowableValues returns config's AllowableValues, falling back to those the
+// store knows for key when config (e.g. a local override) has none.
+func (c ConfigResolver) allowableValues(config *prefabProto.Config, key string) []*prefabProto.ConfigValue {
+	if allowed := config.GetAllowableValues(); len(allowed) > 0 {
+		return allowed
+	}
+
+	if supplier, ok := c.ConfigStore.(AllowableValuesSupplier); ok {
+		return supplier.GetAllowableValues(key)
+	}
+
+	return nil
+}
+
+// validateSchema checks value against the schema config named by config's
+// SchemaKey, if it has one and the schema config exists.
+func (c ConfigResolver) validateSchema(config *prefabProto.Config, value *prefabProto.ConfigValue, contextSet ContextValueGetter) error {
+	if c.SchemaValidator == nil || config.GetSchemaKey() == "" {
+		return nil
+	}
+
+	schemaConfig, exists := c.ConfigStore.GetConfig(config.GetSchemaKey())
+	if !exists {
+		return nil
+	}
+
+	schema := c.RuleEvaluator.EvaluateConfig(schemaConfig, contextSet).Match.GetSchema()
+	if schema == nil {
+		return nil
+	}
+
+	if err := c.SchemaValidator.Validate(schema, value); err != nil {
+		return &SchemaValidationError{Key: config.GetKey(), SchemaKey: config.GetSchemaKey(), Err: err}
+	}
+
+	return nil
+}
+
+// resolvedReason is reason, unless configMatch's value came from a split.
+func resolvedReason(configMatch ConfigMatch, reason EvaluationReason) EvaluationReason {
+	if configMatch.WeightedValueIndex != nil {
+		return ReasonSplit
+	}
+
+	return reason
+}
+
 func (c ConfigResolver) handleProvided(provided *prefabProto.Provided) (string, bool) {
-	if provided.GetSource() == prefabProto.ProvidedSource_ENV_VAR {
-		if provided.Lookup != nil {
-			envValue, envValueExists := c.EnvLookup.LookupEnv(provided.GetLookup())
+	return c.providedResolver().ResolveProvided(provided)
+}
 
-			return envValue, envValueExists
-		}
+func (c ConfigResolver) providedResolver() utils.ProvidedResolver {
+	if c.ProvidedResolver != nil {
+		return c.ProvidedResolver
 	}
 
-	return "", false
+	return utils.EnvProvidedResolver{LookupEnv: c.EnvLookup.LookupEnv}
 }
 
 func coerceValue(value string, valueType prefabProto.Config_ValueType) (any, bool) {
@@ -165,30 +290,81 @@ func coerceValue(value string, valueType prefabProto.Config_ValueType) (any, boo
 	return nil, false
 }
 
-func (c ConfigResolver) handleDecryption(configValue *prefabProto.ConfigValue, contextSet ContextValueGetter) (string, error) {
-	config, configExists := c.ConfigStore.GetConfig(configValue.GetDecryptWith())
-	if configExists {
-		match := c.RuleEvaluator.EvaluateConfig(config, contextSet)
-		if match.IsMatch {
-			key, keyOk, _ := utils.ExtractValue(match.Match)
-			if keyOk {
-				keyStr, ok := key.(string)
-				if !ok {
-					return "", errors.New("secret key is not a string")
-				}
+func (c ConfigResolver) handleDecryption(config *prefabProto.Config, configValue *prefabProto.ConfigValue, contextSet ContextValueGetter, decrypting []string) (string, error) {
+	ciphertext := configValue.GetString_()
 
-				decryptedValue, decryptionError := c.Decrypter.DecryptValue(keyStr, configValue.GetString_())
+	if c.DecryptionCache != nil {
+		if plaintext, cached := c.DecryptionCache.Get(config.GetKey(), config.GetId(), ciphertext); cached {
+			return plaintext, nil
+		}
+	}
 
-				return decryptedValue, decryptionError
-			}
+	secretKeys, err := c.secretKeys(configValue.GetDecryptWith(), contextSet, append(slices.Clip(decrypting), config.GetKey()))
+	if err != nil {
+		return "", err
+	}
+
+	var errs []error
 
-			return "", errors.New("secret key lookup failed")
+	// while a key is being rotated, values may be encrypted with any of them
+	for _, secretKey := range secretKeys {
+		plaintext, err := c.Decrypter.DecryptValue(secretKey, ciphertext)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
 		}
 
-		return "", errors.New("no match in config value")
+		if c.DecryptionCache != nil {
+			c.DecryptionCache.Set(config.GetKey(), config.GetId(), ciphertext, plaintext)
+		}
+
+		return plaintext, nil
 	}
 
-	return "", errors.New("no config value exists")
+	return "", fmt.Errorf("unable to decrypt %q with any of its %d secret keys: %w", config.GetKey(), len(secretKeys), errors.Join(errs...))
+}
+
+// secretKeys returns the keys to try when decrypting a value whose
+// decrypt_with is name. They come from the SecretKeyProvider if it knows name,
+// and otherwise from resolving the config named name, which may hold a string
+// or (while rotating keys) a string list. decrypting holds the configs being
+// decrypted, so a key config that is (eventually) decrypted with one of them
+// is an ErrDecryptionCycle rather than endless recursion.
+func (c ConfigResolver) secretKeys(name string, contextSet ContextValueGetter, decrypting []string) ([]string, error) {
+	if c.SecretKeyProvider != nil {
+		secretKeys, err := c.SecretKeyProvider.SecretKeys(name)
+		if !errors.Is(err, secrets.ErrSecretKeyNotFound) {
+			return secretKeys, err
+		}
+	}
+
+	if slices.Contains(decrypting, name) {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionCycle, append(decrypting, name))
+	}
+
+	keyConfig, configExists := c.ConfigStore.GetConfig(name)
+	if !configExists {
+		return nil, errors.New("no config value exists")
+	}
+
+	match, err := c.resolveValueForConfig(keyConfig, contextSet, name, decrypting)
+	if err != nil {
+		return nil, err
+	}
+
+	if !match.IsMatch {
+		return nil, errors.New("no match in config value")
+	}
+
+	switch value := match.Match.GetType().(type) {
+	case *prefabProto.ConfigValue_String_:
+		return []string{value.String_}, nil
+	case *prefabProto.ConfigValue_StringList:
+		return value.StringList.GetValues(), nil
+	default:
+		return nil, errors.New("secret key is not a string")
+	}
 }
 
 func (c ConfigResolver) handleWeightedValue(configKey string, values *prefabProto.WeightedValues, contextSet ContextValueGetter) (*prefabProto.ConfigValue, int) {
@@ -160,6 +160,7 @@ func TestConfigResolver_ResolveValue(t *testing.T) {
 				OriginalMatch:         configValueOne,
 				ConditionalValueIndex: internal.IntPtr(1),
 				RowIndex:              internal.IntPtr(1),
+				Reason:                internal.ReasonDefault,
 			},
 			mockConfigStoreArgs: []mocks.ConfigMockingArgs{
 				{
@@ -188,6 +189,7 @@ func TestConfigResolver_ResolveValue(t *testing.T) {
 				Match:     nil,
 				IsMatch:   false,
 				ConfigKey: theKey,
+				Reason:    internal.ReasonError,
 			},
 			mockConfigStoreArgs: []mocks.ConfigMockingArgs{
 				{
@@ -207,6 +209,7 @@ func TestConfigResolver_ResolveValue(t *testing.T) {
 				OriginalMatch:         providedConfigValue,
 				ConditionalValueIndex: internal.IntPtr(1),
 				RowIndex:              internal.IntPtr(1),
+				Reason:                internal.ReasonProvided,
 			},
 			mockConfigStoreArgs: []mocks.ConfigMockingArgs{
 				{
@@ -239,6 +242,7 @@ func TestConfigResolver_ResolveValue(t *testing.T) {
 				OriginalMatch:         providedConfigValue,
 				ConditionalValueIndex: internal.IntPtr(1),
 				RowIndex:              internal.IntPtr(1),
+				Reason:                internal.ReasonError,
 			},
 			mockConfigStoreArgs: []mocks.ConfigMockingArgs{
 				{
@@ -269,6 +273,7 @@ func TestConfigResolver_ResolveValue(t *testing.T) {
 				OriginalMatch:         decryptWithConfigValue,
 				ConditionalValueIndex: internal.IntPtr(1),
 				RowIndex:              internal.IntPtr(1),
+				Reason:                internal.ReasonDecrypted,
 			},
 			mockConfigStoreArgs: []mocks.ConfigMockingArgs{
 				{
@@ -315,6 +320,7 @@ func TestConfigResolver_ResolveValue(t *testing.T) {
 				OriginalMatch:         decryptWithConfigValue,
 				ConditionalValueIndex: internal.IntPtr(1),
 				RowIndex:              internal.IntPtr(1),
+				Reason:                internal.ReasonError,
 			},
 			mockConfigStoreArgs: []mocks.ConfigMockingArgs{
 				{
@@ -361,6 +367,7 @@ func TestConfigResolver_ResolveValue(t *testing.T) {
 				OriginalMatch:         decryptWithConfigValue,
 				ConditionalValueIndex: internal.IntPtr(1),
 				RowIndex:              internal.IntPtr(1),
+				Reason:                internal.ReasonError,
 			},
 			mockConfigStoreArgs: []mocks.ConfigMockingArgs{
 				{
@@ -398,6 +405,7 @@ func TestConfigResolver_ResolveValue(t *testing.T) {
 				ConditionalValueIndex: internal.IntPtr(1),
 				RowIndex:              internal.IntPtr(1),
 				WeightedValueIndex:    internal.IntPtr(2),
+				Reason:                internal.ReasonSplit,
 			},
 			mockConfigStoreArgs: []mocks.ConfigMockingArgs{
 				{
@@ -2,10 +2,12 @@ package internal
 
 import (
 	"fmt"
+	"log/slog"
 	"math"
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/anyhelpers"
@@ -18,18 +20,34 @@ type ConditionMatch struct {
 	Match                 *prefabProto.ConfigValue
 	RowIndex              *int
 	ConditionalValueIndex *int
+	MatchedCriteria       []*prefabProto.Criterion
 	IsMatch               bool
 }
 
+// LookupKeyPropertyName is the context property LOOKUP_KEY_IN and
+// LOOKUP_KEY_NOT_IN criteria check when they don't name one.
+const LookupKeyPropertyName = "user.key"
+
 type ConfigRuleEvaluator struct {
 	configStore          ConfigStoreGetter
 	projectEnvIDSupplier ProjectEnvIDSupplier
+	// OnUnknownOperator, if set, is called with each criterion whose
+	// operator the evaluator doesn't support, every time one is evaluated
+	// (the error is only logged the first time for each operator). Such
+	// criteria never match.
+	OnUnknownOperator func(criterion *prefabProto.Criterion)
+	// CustomOperators evaluates criteria with operators that aren't built in.
+	CustomOperators *OperatorRegistry
+	// loggedUnknownOperators holds the unknown operators already logged, so
+	// each is only logged once.
+	loggedUnknownOperators sync.Map
 }
 
 func NewConfigRuleEvaluator(configStore ConfigStoreGetter, projectEnvIDSupplier ProjectEnvIDSupplier) *ConfigRuleEvaluator {
 	return &ConfigRuleEvaluator{
 		configStore:          configStore,
 		projectEnvIDSupplier: projectEnvIDSupplier,
+		CustomOperators:      NewOperatorRegistry(),
 	}
 }
 
@@ -71,6 +89,7 @@ func (cve *ConfigRuleEvaluator) EvaluateRow(row *prefabProto.ConfigRow, contextS
 			conditionMatch.RowIndex = &rowIndex
 			conditionMatch.ConditionalValueIndex = &conditionalValueIndex
 			conditionMatch.Match = matchedValue
+			conditionMatch.MatchedCriteria = conditionalValue.GetCriteria()
 
 			break
 		}
@@ -119,8 +138,15 @@ func contextValueToStringSlice(contextValue interface{}) []string {
 }
 
 func (cve *ConfigRuleEvaluator) EvaluateCriterion(criterion *prefabProto.Criterion, contextSet ContextValueGetter) bool {
+	propertyName := criterion.GetPropertyName()
+
+	isLookupKeyOperator := criterion.GetOperator() == prefabProto.Criterion_LOOKUP_KEY_IN || criterion.GetOperator() == prefabProto.Criterion_LOOKUP_KEY_NOT_IN
+	if isLookupKeyOperator && propertyName == "" {
+		propertyName = LookupKeyPropertyName
+	}
+
 	// get the value from context
-	contextValue, contextValueExists := contextSet.GetContextValue(criterion.GetPropertyName())
+	contextValue, contextValueExists := contextSet.GetContextValue(propertyName)
 
 	// Special handling for "prefab.current-time" and "reforge.current-time" properties
 	if criterion.GetPropertyName() == "prefab.current-time" || criterion.GetPropertyName() == "reforge.current-time" {
@@ -137,6 +163,16 @@ func (cve *ConfigRuleEvaluator) EvaluateCriterion(criterion *prefabProto.Criteri
 		return false
 	case prefabProto.Criterion_ALWAYS_TRUE:
 		return true
+	case prefabProto.Criterion_LOOKUP_KEY_IN, prefabProto.Criterion_LOOKUP_KEY_NOT_IN:
+		if err == nil && contextValueExists && contextValue != nil {
+			if stringSliceMatchValue, matchValueIsStringSlice := matchValue.([]string); matchValueIsStringSlice {
+				matchFound := stringInSlice(contextValueToString(contextValue), stringSliceMatchValue)
+
+				return matchFound == (criterion.GetOperator() == prefabProto.Criterion_LOOKUP_KEY_IN)
+			}
+		}
+
+		return criterion.GetOperator() == prefabProto.Criterion_LOOKUP_KEY_NOT_IN
 	case prefabProto.Criterion_PROP_ENDS_WITH_ONE_OF, prefabProto.Criterion_PROP_DOES_NOT_END_WITH_ONE_OF:
 		if err == nil && contextValueExists {
 			stringContextValue := contextValueToString(contextValue)
@@ -288,10 +324,26 @@ func (cve *ConfigRuleEvaluator) EvaluateCriterion(criterion *prefabProto.Criteri
 			}
 
 		}
+	default:
+		if customOperator, ok := cve.CustomOperators.Lookup(criterion.GetOperator()); ok {
+			return customOperator(criterion, contextValue, contextValueExists)
+		}
+
+		cve.unknownOperator(criterion)
 	}
 	return false
 }
 
+func (cve *ConfigRuleEvaluator) unknownOperator(criterion *prefabProto.Criterion) {
+	if _, logged := cve.loggedUnknownOperators.LoadOrStore(criterion.GetOperator(), true); !logged {
+		slog.Error("unknown criterion operator; criteria using it will not match", "operator", criterion.GetOperator().String(), "property", criterion.GetPropertyName())
+	}
+
+	if cve.OnUnknownOperator != nil {
+		cve.OnUnknownOperator(criterion)
+	}
+}
+
 func dateToMillis(val any) (int64, error) {
 	if anyhelpers.IsNumber(val) {
 		if int64Value, err := anyhelpers.ToInt64(val); err == nil {
@@ -1,6 +1,9 @@
 package internal_test
 
 import (
+	"bytes"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
@@ -562,6 +565,143 @@ func (suite *ConfigRuleTestSuite) TestPropIsNotOneOf() {
 	}
 }
 
+func (suite *ConfigRuleTestSuite) TestLookupKeyIn() {
+	operator := prefabProto.Criterion_LOOKUP_KEY_IN
+	defaultValueToMatch := testutils.CreateConfigValueAndAssertOk(suite.T(), []string{"user-123", "user-456"})
+	alternateValueToMatch := testutils.CreateConfigValueAndAssertOk(suite.T(), []string{"1", "2", "3"})
+
+	tests := []struct {
+		name                string
+		propertyName        string
+		contextPropertyName string
+		valueToMatch        *prefabProto.ConfigValue
+		contextValue        interface{}
+		contextValueExists  bool
+		expected            bool
+	}{
+		{"returns true when in set", "user.key", "user.key", defaultValueToMatch, "user-123", true, true},
+		{"returns false when not in set", "user.key", "user.key", defaultValueToMatch, "user-789", true, false},
+		{"returns false when context value does not exist", "user.key", "user.key", defaultValueToMatch, nil, false, false},
+		{"returns false when valueToMatch is not a string slice", "user.key", "user.key", testutils.CreateConfigValueAndAssertOk(suite.T(), "user-123"), "user-123", true, false},
+		{"returns true when the non-string matches the slice (it is coerced)", "user.key", "user.key", alternateValueToMatch, 2, true, true},
+		{"uses the named property", "device.key", "device.key", defaultValueToMatch, "user-456", true, true},
+		{"defaults to the user key", "", internal.LookupKeyPropertyName, defaultValueToMatch, "user-456", true, true},
+	}
+
+	for _, testCase := range tests {
+		suite.Run(testCase.name, func() {
+			mockContext, assertMockCalled := suite.setupMockContext(testCase.contextPropertyName, testCase.contextValue, testCase.contextValueExists)
+			defer assertMockCalled()
+
+			criterion := &prefabProto.Criterion{Operator: operator, ValueToMatch: testCase.valueToMatch, PropertyName: testCase.propertyName}
+			isMatch := suite.evaluator.EvaluateCriterion(criterion, mockContext)
+			suite.Equal(testCase.expected, isMatch)
+		})
+	}
+}
+
+func (suite *ConfigRuleTestSuite) TestLookupKeyNotIn() {
+	operator := prefabProto.Criterion_LOOKUP_KEY_NOT_IN
+	defaultValueToMatch := testutils.CreateConfigValueAndAssertOk(suite.T(), []string{"user-123", "user-456"})
+	alternateValueToMatch := testutils.CreateConfigValueAndAssertOk(suite.T(), []string{"1", "2", "3"})
+
+	tests := []struct {
+		name                string
+		propertyName        string
+		contextPropertyName string
+		valueToMatch        *prefabProto.ConfigValue
+		contextValue        interface{}
+		contextValueExists  bool
+		expected            bool
+	}{
+		{"returns false when in set", "user.key", "user.key", defaultValueToMatch, "user-123", true, false},
+		{"returns true when not in set", "user.key", "user.key", defaultValueToMatch, "user-789", true, true},
+		{"returns true when context value does not exist", "user.key", "user.key", defaultValueToMatch, nil, false, true},
+		{"returns true when valueToMatch is not a string slice", "user.key", "user.key", testutils.CreateConfigValueAndAssertOk(suite.T(), "user-123"), "user-123", true, true},
+		{"returns false when the non-string matches the slice (it is coerced)", "user.key", "user.key", alternateValueToMatch, 2, true, false},
+		{"uses the named property", "device.key", "device.key", defaultValueToMatch, "user-456", true, false},
+		{"defaults to the user key", "", internal.LookupKeyPropertyName, defaultValueToMatch, "user-789", true, true},
+	}
+
+	for _, testCase := range tests {
+		suite.Run(testCase.name, func() {
+			mockContext, assertMockCalled := suite.setupMockContext(testCase.contextPropertyName, testCase.contextValue, testCase.contextValueExists)
+			defer assertMockCalled()
+
+			criterion := &prefabProto.Criterion{Operator: operator, ValueToMatch: testCase.valueToMatch, PropertyName: testCase.propertyName}
+			isMatch := suite.evaluator.EvaluateCriterion(criterion, mockContext)
+			suite.Equal(testCase.expected, isMatch)
+		})
+	}
+}
+
+func (suite *ConfigRuleTestSuite) TestUnknownOperator() {
+	var unknownCriteria []*prefabProto.Criterion
+
+	suite.evaluator.OnUnknownOperator = func(criterion *prefabProto.Criterion) {
+		unknownCriteria = append(unknownCriteria, criterion)
+	}
+
+	mockContext, assertMockCalled := suite.setupMockContext("user.key", "user-123", true)
+	defer assertMockCalled()
+
+	criterion := &prefabProto.Criterion{
+		Operator:     prefabProto.Criterion_CriterionOperator(999),
+		ValueToMatch: testutils.CreateConfigValueAndAssertOk(suite.T(), []string{"user-123"}),
+		PropertyName: "user.key",
+	}
+
+	var output bytes.Buffer
+
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&output, nil)))
+
+	defer slog.SetDefault(previous)
+
+	suite.False(suite.evaluator.EvaluateCriterion(criterion, mockContext))
+	suite.False(suite.evaluator.EvaluateCriterion(criterion, mockContext))
+	suite.Equal([]*prefabProto.Criterion{criterion, criterion}, unknownCriteria)
+	suite.Equal(1, strings.Count(output.String(), "unknown criterion operator"), "logged once per operator")
+}
+
+func (suite *ConfigRuleTestSuite) TestCustomOperator() {
+	const propHasPrefixLength = prefabProto.Criterion_CriterionOperator(1001)
+
+	unknownOperatorCalled := false
+	suite.evaluator.OnUnknownOperator = func(*prefabProto.Criterion) {
+		unknownOperatorCalled = true
+	}
+
+	suite.Require().NoError(suite.evaluator.CustomOperators.Register(propHasPrefixLength, func(criterion *prefabProto.Criterion, contextValue any, contextValueExists bool) bool {
+		stringValue, isString := contextValue.(string)
+
+		return contextValueExists && isString && int64(len(stringValue)) >= criterion.GetValueToMatch().GetInt()
+	}))
+
+	tests := []struct {
+		name               string
+		contextValue       interface{}
+		contextValueExists bool
+		expected           bool
+	}{
+		{"returns true when the operator matches", "abcdef", true, true},
+		{"returns false when the operator does not match", "abc", true, false},
+		{"passes along a missing context value", nil, false, false},
+	}
+
+	for _, testCase := range tests {
+		suite.Run(testCase.name, func() {
+			mockContext, assertMockCalled := suite.setupMockContext("user.name", testCase.contextValue, testCase.contextValueExists)
+			defer assertMockCalled()
+
+			criterion := &prefabProto.Criterion{Operator: propHasPrefixLength, ValueToMatch: testutils.CreateConfigValueAndAssertOk(suite.T(), int64(5)), PropertyName: "user.name"}
+			suite.Equal(testCase.expected, suite.evaluator.EvaluateCriterion(criterion, mockContext))
+		})
+	}
+
+	suite.False(unknownOperatorCalled)
+}
+
 func (suite *ConfigRuleTestSuite) TestHierarchicalMatch() {
 	operator := prefabProto.Criterion_HIERARCHICAL_MATCH
 	contextPropertyName := "team.path"
@@ -0,0 +1,166 @@
+// Package connection tracks the state of the client's connection to the
+// Prefab API.
+package connection
+
+import (
+	"sync"
+	"time"
+)
+
+// State is where the API config store is in loading and following configs.
+type State string
+
+const (
+	// Initializing means the configs haven't been loaded from the API yet.
+	Initializing State = "initializing"
+	// Loaded means the configs were loaded from the API and updates come by
+	// polling (or will come once the stream connects). Clients without an
+	// API source are Loaded as soon as they're created.
+	Loaded State = "loaded"
+	// Streaming means the SSE stream is connected and delivering updates.
+	Streaming State = "streaming"
+	// Reconnecting means the SSE stream dropped and is being reopened.
+	Reconnecting State = "reconnecting"
+	// Failed means the configs couldn't be loaded from the API.
+	Failed State = "failed"
+)
+
+// Status is a snapshot of the connection.
+type Status struct {
+	// Since is when State was entered.
+	Since time.Time
+	// LastSync is when the API last confirmed the configs were up to date
+	// (a load, an update, a keep-alive or an unchanged poll). It is zero
+	// until the first sync.
+	LastSync time.Time
+	// Err is why the connection is Failed or Reconnecting, if known.
+	Err   error
+	State State
+	// HighWatermark is the ID of the newest config received.
+	HighWatermark int64
+	// Stale is set when the configs haven't been synced for longer than the
+	// client's maximum staleness, and cleared by the next sync.
+	Stale bool
+}
+
+// StateChange is passed to listeners when the connection moves from Old to
+// Status.State, or when it becomes stale or fresh again.
+type StateChange struct {
+	Old      State
+	Status   Status
+	WasStale bool
+}
+
+// Tracker holds the connection's Status and tells listeners when its State
+// or staleness changes. A nil *Tracker ignores updates.
+type Tracker struct {
+	listeners map[int]func(StateChange)
+	status    Status
+	nextID    int
+	mutex     sync.RWMutex
+	// notifyMutex keeps listeners from seeing changes out of order.
+	notifyMutex sync.Mutex
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		listeners: make(map[int]func(StateChange)),
+		status:    Status{State: Initializing, Since: time.Now()},
+	}
+}
+
+// Subscribe registers listener and returns a function that unregisters it.
+func (t *Tracker) Subscribe(listener func(StateChange)) (unsubscribe func()) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	id := t.nextID
+	t.nextID++
+	t.listeners[id] = listener
+
+	return func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+
+		delete(t.listeners, id)
+	}
+}
+
+// Status returns the current status.
+func (t *Tracker) Status() Status {
+	if t == nil {
+		return Status{State: Initializing}
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.status
+}
+
+// SetState moves the connection to state, recording err as the reason, and
+// tells listeners if the state changed.
+func (t *Tracker) SetState(state State, err error) {
+	t.update(func(status *Status) {
+		if status.State != state {
+			status.Since = time.Now()
+		}
+
+		status.State = state
+		status.Err = err
+	})
+}
+
+// SetStale marks the configs as stale (or not) and tells listeners if that's
+// a change.
+func (t *Tracker) SetStale(stale bool) {
+	t.update(func(status *Status) {
+		status.Stale = stale
+	})
+}
+
+// Synced records that the API just confirmed the configs are up to date as
+// of highWatermark, so they're no longer stale.
+func (t *Tracker) Synced(highWatermark int64) {
+	t.update(func(status *Status) {
+		status.LastSync = time.Now()
+		status.HighWatermark = max(status.HighWatermark, highWatermark)
+		status.Stale = false
+	})
+}
+
+// update applies change to the status and tells listeners if the state or
+// staleness changed.
+func (t *Tracker) update(change func(status *Status)) {
+	if t == nil {
+		return
+	}
+
+	t.notifyMutex.Lock()
+	defer t.notifyMutex.Unlock()
+
+	t.mutex.Lock()
+
+	old := t.status
+	change(&t.status)
+
+	if old.State == t.status.State && old.Stale == t.status.Stale {
+		t.mutex.Unlock()
+
+		return
+	}
+
+	stateChange := StateChange{Old: old.State, WasStale: old.Stale, Status: t.status}
+
+	listeners := make([]func(StateChange), 0, len(t.listeners))
+	for _, listener := range t.listeners {
+		listeners = append(listeners, listener)
+	}
+
+	t.mutex.Unlock()
+
+	// notify outside the lock so listeners can read the status
+	for _, listener := range listeners {
+		listener(stateChange)
+	}
+}
@@ -0,0 +1,87 @@
+package connection_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/connection"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := connection.NewTracker()
+
+	assert.Equal(t, connection.Initializing, tracker.Status().State)
+	assert.True(t, tracker.Status().LastSync.IsZero())
+
+	var changes []connection.StateChange
+
+	unsubscribe := tracker.Subscribe(func(change connection.StateChange) { changes = append(changes, change) })
+
+	tracker.SetState(connection.Loaded, nil)
+	tracker.Synced(7)
+	tracker.Synced(3)
+	tracker.SetState(connection.Loaded, nil)
+
+	errDropped := errors.New("dropped")
+	tracker.SetState(connection.Reconnecting, errDropped)
+
+	status := tracker.Status()
+	assert.Equal(t, connection.Reconnecting, status.State)
+	assert.Equal(t, int64(7), status.HighWatermark, "the high watermark never goes backwards")
+	assert.False(t, status.LastSync.IsZero())
+	assert.Equal(t, errDropped, status.Err)
+
+	unsubscribe()
+	tracker.SetState(connection.Streaming, nil)
+
+	assert.Nil(t, tracker.Status().Err)
+
+	if assert.Len(t, changes, 2, "unchanged states and changes after unsubscribing aren't reported") {
+		assert.Equal(t, connection.Initializing, changes[0].Old)
+		assert.Equal(t, connection.Loaded, changes[0].Status.State)
+		assert.Equal(t, connection.Loaded, changes[1].Old)
+		assert.Equal(t, connection.Reconnecting, changes[1].Status.State)
+		assert.Equal(t, errDropped, changes[1].Status.Err)
+	}
+}
+
+func TestNilTracker(t *testing.T) {
+	var tracker *connection.Tracker
+
+	tracker.SetState(connection.Streaming, nil)
+	tracker.Synced(1)
+
+	assert.Equal(t, connection.Initializing, tracker.Status().State)
+}
+
+func TestTrackerStaleness(t *testing.T) {
+	tracker := connection.NewTracker()
+	tracker.SetState(connection.Streaming, nil)
+
+	var changes []connection.StateChange
+
+	tracker.Subscribe(func(change connection.StateChange) { changes = append(changes, change) })
+
+	tracker.SetStale(true)
+	tracker.SetStale(true)
+
+	assert.True(t, tracker.Status().Stale)
+
+	tracker.Synced(1)
+
+	assert.False(t, tracker.Status().Stale, "a sync makes the configs fresh")
+
+	tracker.Synced(2)
+
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, connection.Streaming, changes[0].Old)
+		assert.False(t, changes[0].WasStale)
+		assert.True(t, changes[0].Status.Stale)
+
+		assert.True(t, changes[1].WasStale)
+		assert.False(t, changes[1].Status.Stale)
+		assert.Equal(t, connection.Streaming, changes[1].Status.State)
+	}
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// ErrBuiltInOperator is returned when registering a custom operator under the
+// number of an operator the evaluator already implements.
+var ErrBuiltInOperator = errors.New("operator is built in")
+
+// CustomOperator reports whether criterion matches. contextValue is the value
+// of the criterion's property, if contextValueExists.
+type CustomOperator func(criterion *prefabProto.Criterion, contextValue any, contextValueExists bool) bool
+
+// OperatorRegistry holds custom operators keyed by operator number. Configs
+// refer to them by number, e.g. `"operator": 1001` in a JSON datafile.
+type OperatorRegistry struct {
+	operators map[prefabProto.Criterion_CriterionOperator]CustomOperator
+	mutex     sync.RWMutex
+}
+
+func NewOperatorRegistry() *OperatorRegistry {
+	return &OperatorRegistry{operators: make(map[prefabProto.Criterion_CriterionOperator]CustomOperator)}
+}
+
+// Register adds (or replaces) the custom operator for operator. Built-in
+// operators can't be replaced.
+func (r *OperatorRegistry) Register(operator prefabProto.Criterion_CriterionOperator, fn CustomOperator) error {
+	if _, builtIn := prefabProto.Criterion_CriterionOperator_name[int32(operator)]; builtIn {
+		return fmt.Errorf("%w: %s", ErrBuiltInOperator, operator)
+	}
+
+	if fn == nil {
+		return fmt.Errorf("custom operator %d has no function", operator)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.operators[operator] = fn
+
+	return nil
+}
+
+// Lookup returns the custom operator registered for operator.
+func (r *OperatorRegistry) Lookup(operator prefabProto.Criterion_CriterionOperator) (CustomOperator, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	fn, ok := r.operators[operator]
+
+	return fn, ok
+}
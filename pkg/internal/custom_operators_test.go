@@ -0,0 +1,32 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestOperatorRegistry(t *testing.T) {
+	registry := internal.NewOperatorRegistry()
+	alwaysTrue := func(*prefabProto.Criterion, any, bool) bool { return true }
+
+	require.ErrorIs(t, registry.Register(prefabProto.Criterion_PROP_IS_ONE_OF, alwaysTrue), internal.ErrBuiltInOperator)
+	require.Error(t, registry.Register(1001, nil))
+	require.NoError(t, registry.Register(1001, alwaysTrue))
+
+	fn, ok := registry.Lookup(1001)
+	require.True(t, ok)
+	assert.True(t, fn(nil, nil, false))
+
+	_, ok = registry.Lookup(1002)
+	assert.False(t, ok)
+
+	var nilRegistry *internal.OperatorRegistry
+
+	_, ok = nilRegistry.Lookup(1001)
+	assert.False(t, ok)
+}
@@ -0,0 +1,66 @@
+package internal
+
+import "sync"
+
+// DecryptionCache remembers decrypted values so each encrypted value is only
+// decrypted once per config version. Entries for a config are dropped when a
+// new version (a higher config ID) is decrypted, or when the config changes
+// (see Evict).
+type DecryptionCache struct {
+	configs map[string]*decryptedConfig
+	mutex   sync.RWMutex
+}
+
+type decryptedConfig struct {
+	values   map[string]string
+	configID int64
+}
+
+func NewDecryptionCache() *DecryptionCache {
+	return &DecryptionCache{configs: make(map[string]*decryptedConfig)}
+}
+
+// Get returns the cached decryption of ciphertext for version configID of
+// configKey.
+func (c *DecryptionCache) Get(configKey string, configID int64, ciphertext string) (string, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	config, exists := c.configs[configKey]
+	if !exists || config.configID != configID {
+		return "", false
+	}
+
+	plaintext, exists := config.values[ciphertext]
+
+	return plaintext, exists
+}
+
+// Set caches the decryption of ciphertext for version configID of configKey.
+// Values for older versions are discarded; values for versions older than the
+// cached one are ignored.
+func (c *DecryptionCache) Set(configKey string, configID int64, ciphertext string, plaintext string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	config, exists := c.configs[configKey]
+
+	switch {
+	case exists && config.configID > configID:
+		return
+	case !exists || config.configID < configID:
+		config = &decryptedConfig{configID: configID, values: make(map[string]string)}
+		c.configs[configKey] = config
+	}
+
+	config.values[ciphertext] = plaintext
+}
+
+// Evict drops the cached values for configKey. The client calls it when the
+// config is updated or deleted so stale plaintext isn't kept around.
+func (c *DecryptionCache) Evict(configKey string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.configs, configKey)
+}
@@ -0,0 +1,44 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+)
+
+func TestDecryptionCache(t *testing.T) {
+	cache := internal.NewDecryptionCache()
+
+	_, cached := cache.Get("secret", 1, "ciphertext")
+	assert.False(t, cached)
+
+	cache.Set("secret", 1, "ciphertext", "plaintext")
+
+	plaintext, cached := cache.Get("secret", 1, "ciphertext")
+	assert.True(t, cached)
+	assert.Equal(t, "plaintext", plaintext)
+
+	_, cached = cache.Get("secret", 2, "ciphertext")
+	assert.False(t, cached, "a new config version isn't cached yet")
+
+	cache.Set("secret", 2, "new-ciphertext", "new-plaintext")
+
+	_, cached = cache.Get("secret", 1, "ciphertext")
+	assert.False(t, cached, "older versions are dropped")
+
+	cache.Set("secret", 1, "ciphertext", "plaintext")
+
+	_, cached = cache.Get("secret", 1, "ciphertext")
+	assert.False(t, cached, "older versions aren't cached again")
+
+	plaintext, cached = cache.Get("secret", 2, "new-ciphertext")
+	assert.True(t, cached)
+	assert.Equal(t, "new-plaintext", plaintext)
+
+	cache.Evict("secret")
+
+	_, cached = cache.Get("secret", 2, "new-ciphertext")
+	assert.False(t, cached, "evicted values are gone")
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"strings"
@@ -12,7 +13,50 @@ type Encryption struct{}
 
 var ErrInvalidValueFormat = errors.New("invalid value format")
 
-const validPartCount = 3
+const (
+	validPartCount = 3
+	secretKeySize  = 32 // AES-256
+	nonceSize      = 12
+)
+
+// GenerateSecretKey returns a new random hex-encoded AES-256 key.
+func GenerateSecretKey() (string, error) {
+	secretKey := make([]byte, secretKeySize)
+	if _, err := rand.Read(secretKey); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secretKey), nil
+}
+
+// EncryptValue encrypts value with the hex-encoded secret key, in the
+// data--iv--authtag format DecryptValue reads.
+func (d *Encryption) EncryptValue(secretKeyString string, value string) (string, error) {
+	secretKey, err := hex.DecodeString(secretKeyString)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(secretKey)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCMWithNonceSize(block, nonceSize)
+	if err != nil {
+		return "", err
+	}
+
+	iv := make([]byte, nonceSize)
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nil, iv, []byte(value), nil)
+	data, authTag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	return strings.Join([]string{hex.EncodeToString(data), hex.EncodeToString(iv), hex.EncodeToString(authTag)}, "--"), nil
+}
 
 func (d *Encryption) DecryptValue(secretKeyString string, value string) (string, error) {
 	// Decode the hex-encoded secret key
@@ -1,6 +1,7 @@
 package internal_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -42,6 +43,29 @@ func (suite *EncryptionTestSuite) TestDecryptionFails() {
 	})
 }
 
+func (suite *EncryptionTestSuite) TestEncryptionRoundTrips() {
+	secretKey, err := internal.GenerateSecretKey()
+	suite.Require().NoError(err)
+	suite.Len(secretKey, 64)
+
+	encryptedValue, err := suite.decrypter.EncryptValue(secretKey, "james-was-here")
+	suite.Require().NoError(err)
+	suite.Len(strings.Split(encryptedValue, "--"), 3)
+
+	decryptedValue, err := suite.decrypter.DecryptValue(secretKey, encryptedValue)
+	suite.Require().NoError(err)
+	suite.Equal("james-was-here", decryptedValue)
+
+	otherKey, err := internal.GenerateSecretKey()
+	suite.Require().NoError(err)
+
+	_, err = suite.decrypter.DecryptValue(otherKey, encryptedValue)
+	suite.Error(err)
+
+	_, err = suite.decrypter.EncryptValue("not hex", "james-was-here")
+	suite.Error(err)
+}
+
 // In order for 'go test' to run this suite, we need to create
 // a normal test function and pass our suite to suite.Run
 func TestDecrypterTestSuite(t *testing.T) {
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"fmt"
+
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// EvaluationReason explains where the value of a ConfigMatch came from.
+type EvaluationReason int
+
+const (
+	// ReasonUnknown is the zero value and is never produced by the resolver.
+	ReasonUnknown EvaluationReason = iota
+	// ReasonDefault means the first matching conditional value had no criteria (or only ALWAYS_TRUE).
+	ReasonDefault
+	// ReasonTargetingMatch means the value was selected by one or more targeting criteria.
+	ReasonTargetingMatch
+	// ReasonSegmentMatch means the selecting criteria included a segment (IN_SEG / NOT_IN_SEG).
+	ReasonSegmentMatch
+	// ReasonSplit means the value was picked from weighted values.
+	ReasonSplit
+	// ReasonProvided means the value was read from an environment variable.
+	ReasonProvided
+	// ReasonDecrypted means the value was decrypted with a secret key.
+	ReasonDecrypted
+	// ReasonNoMatch means the config exists but no row or conditional value matched.
+	ReasonNoMatch
+	// ReasonError means the config was missing or couldn't be evaluated.
+	ReasonError
+	// ReasonDisallowedValue means the config resolved to a value outside its
+	// allowable values, so the first allowable value was used instead.
+	ReasonDisallowedValue
+)
+
+func (r EvaluationReason) String() string {
+	switch r {
+	case ReasonUnknown:
+		return "UNKNOWN"
+	case ReasonDefault:
+		return "DEFAULT"
+	case ReasonTargetingMatch:
+		return "TARGETING_MATCH"
+	case ReasonSegmentMatch:
+		return "SEGMENT_MATCH"
+	case ReasonSplit:
+		return "SPLIT"
+	case ReasonProvided:
+		return "PROVIDED"
+	case ReasonDecrypted:
+		return "DECRYPTED"
+	case ReasonNoMatch:
+		return "NO_MATCH"
+	case ReasonError:
+		return "ERROR"
+	case ReasonDisallowedValue:
+		return "DISALLOWED_VALUE"
+	default:
+		return fmt.Sprintf("EvaluationReason(%d)", int(r))
+	}
+}
+
+// CounterReason maps r onto the reason reported in evaluation summaries.
+// The telemetry schema only defines UNKNOWN so far, so every reason maps to it
+// until the proto grows more values.
+func (r EvaluationReason) CounterReason() prefabProto.ConfigEvaluationCounter_Reason {
+	return prefabProto.ConfigEvaluationCounter_UNKNOWN
+}
+
+// reasonForCriteria picks the reason for a conditional value that matched
+// with the given criteria.
+func reasonForCriteria(criteria []*prefabProto.Criterion) EvaluationReason {
+	reason := ReasonDefault
+
+	for _, criterion := range criteria {
+		switch criterion.GetOperator() {
+		case prefabProto.Criterion_ALWAYS_TRUE:
+		case prefabProto.Criterion_IN_SEG, prefabProto.Criterion_NOT_IN_SEG:
+			return ReasonSegmentMatch
+		default:
+			reason = ReasonTargetingMatch
+		}
+	}
+
+	return reason
+}
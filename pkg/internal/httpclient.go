@@ -7,6 +7,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"sync"
 
 	"google.golang.org/protobuf/proto"
 
@@ -14,9 +15,20 @@ import (
 	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
 
+// ErrNotModified is returned by Load when the API reports (via the ETag sent
+// with a previous response) that nothing has changed since that response.
+var ErrNotModified = errors.New("configs not modified")
+
 type HTTPClient struct {
 	Options *options.Options
-	URLs    []string
+	client  *http.Client
+	// etagURI and etagValue are the URI of the last response with an ETag
+	// and that ETag. Only the last one is kept: the URI includes the offset,
+	// which changes with every update.
+	etagURI   string
+	etagValue string
+	URLs      []string
+	etagMutex sync.Mutex
 }
 
 func BuildHTTPClient(options options.Options) (*HTTPClient, error) {
@@ -25,12 +37,12 @@ func BuildHTTPClient(options options.Options) (*HTTPClient, error) {
 		return nil, err
 	}
 
-	client := HTTPClient{Options: &options, URLs: apiURLs}
+	client := HTTPClient{Options: &options, URLs: apiURLs, client: options.HTTPClientOrDefault()}
 
 	return &client, nil
 }
 
-func (c *HTTPClient) Load(offset int64) (*prefabProto.Configs, error) {
+func (c *HTTPClient) Load(ctx context.Context, offset int64) (*prefabProto.Configs, error) {
 	apiKey, err := c.Options.APIKeySettingOrEnvVar()
 	if err != nil {
 		return nil, err
@@ -39,8 +51,16 @@ func (c *HTTPClient) Load(offset int64) (*prefabProto.Configs, error) {
 	for _, url := range c.URLs {
 		uri := fmt.Sprintf("%s/api/v1/configs/%d", url, offset)
 
-		configs, err := c.LoadFromURI(uri, apiKey, offset)
+		configs, err := c.LoadFromURI(ctx, uri, apiKey, offset)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			if errors.Is(err, ErrNotModified) {
+				return nil, err
+			}
+
 			slog.Error("Error loading from URI", "err", err)
 
 			continue
@@ -52,11 +72,11 @@ func (c *HTTPClient) Load(offset int64) (*prefabProto.Configs, error) {
 	return nil, errors.New("error loading configs from all URIs")
 }
 
-func (c *HTTPClient) LoadFromURI(uri string, apiKey string, offset int64) (*prefabProto.Configs, error) {
+func (c *HTTPClient) LoadFromURI(ctx context.Context, uri string, apiKey string, offset int64) (*prefabProto.Configs, error) {
 	slog.Debug("Getting data from "+uri, "offset", offset)
 
 	// Perform the HTTP GET request
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, uri, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +84,11 @@ func (c *HTTPClient) LoadFromURI(uri string, apiKey string, offset int64) (*pref
 	req.SetBasicAuth("1", apiKey)
 	req.Header.Add("X-PrefabCloud-Client-Version", ClientVersionHeader)
 
-	resp, err := http.DefaultClient.Do(req)
+	if etag := c.etag(uri); etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +102,10 @@ func (c *HTTPClient) LoadFromURI(uri string, apiKey string, offset int64) (*pref
 		}
 	}(resp.Body)
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("error loading configs. Response code %s", resp.Status)
 	}
@@ -97,6 +125,34 @@ func (c *HTTPClient) LoadFromURI(uri string, apiKey string, offset int64) (*pref
 	if err != nil {
 		return nil, err
 	}
+
+	c.setETag(uri, resp.Header.Get("ETag"))
 	// Use your protobuf message (msg) as needed
 	return &msg, nil
 }
+
+func (c *HTTPClient) etag(uri string) string {
+	c.etagMutex.Lock()
+	defer c.etagMutex.Unlock()
+
+	if uri != c.etagURI {
+		return ""
+	}
+
+	return c.etagValue
+}
+
+func (c *HTTPClient) setETag(uri string, etag string) {
+	c.etagMutex.Lock()
+	defer c.etagMutex.Unlock()
+
+	if etag == "" {
+		if uri == c.etagURI {
+			c.etagURI, c.etagValue = "", ""
+		}
+
+		return
+	}
+
+	c.etagURI, c.etagValue = uri, etag
+}
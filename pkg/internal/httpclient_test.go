@@ -0,0 +1,54 @@
+package internal_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
+)
+
+func TestHTTPClientKeepsOnlyTheLatestETag(t *testing.T) {
+	sentETags := make(chan string, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sentETags <- r.Header.Get("If-None-Match")
+
+		etag := fmt.Sprintf("%q", r.URL.Path)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+	}))
+	defer server.Close()
+
+	client, err := internal.BuildHTTPClient(options.Options{APIKey: "does-not-matter", APIURLs: []string{server.URL}})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = client.Load(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, <-sentETags)
+
+	_, err = client.Load(ctx, 5)
+	require.NoError(t, err)
+	assert.Empty(t, <-sentETags)
+
+	_, err = client.Load(ctx, 5)
+	require.ErrorIs(t, err, internal.ErrNotModified)
+	assert.Equal(t, `"/api/v1/configs/5"`, <-sentETags)
+
+	_, err = client.Load(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, <-sentETags, "the ETag for offset 0 was replaced by the one for offset 5")
+}
@@ -431,7 +431,7 @@ func (suite *GeneratedTestSuite) executeGetOrEnabledTest(filename string) {
 				case "unable_to_decrypt":
 					suite.Require().ErrorContains(result.err, "message authentication failed")
 				case "missing_env_var":
-					suite.Require().ErrorContains(result.err, "environment variable does not exist")
+					suite.Require().ErrorIs(result.err, prefab.ErrProvidedValueNotFound)
 				case "initialization_timeout":
 					suite.Require().ErrorContains(result.err, "initialization timeout")
 				case "missing_default":
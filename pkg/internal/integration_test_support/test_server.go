@@ -1,11 +1,14 @@
 package integrationtestsupport
 
 import (
+	"encoding/base64"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"testing"
 
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/protobuf/proto"
@@ -35,6 +38,53 @@ func StartTestServer() (*[]*http.Request, *httptest.Server) {
 	return &requests, ts
 }
 
+// StartFakeAPIServer serves initial from the configs endpoint and streams
+// whatever is sent to the returned channel over SSE.
+func StartFakeAPIServer(t *testing.T, initial *prefabProto.Configs) (*httptest.Server, chan<- *prefabProto.Configs) {
+	t.Helper()
+
+	updates := make(chan *prefabProto.Configs)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/api/v1/configs/"):
+			body, err := proto.Marshal(initial)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+
+			_, _ = w.Write(body)
+		case r.URL.Path == "/api/v1/sse/config":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+
+			for {
+				select {
+				case <-r.Context().Done():
+					return
+				case configs := <-updates:
+					body, err := proto.Marshal(configs)
+					if err != nil {
+						return
+					}
+
+					_, _ = fmt.Fprintf(w, "data: %s\n\n", base64.StdEncoding.EncodeToString(body))
+					w.(http.Flusher).Flush()
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, updates
+}
+
 func RequestBodyToTelemetryEventsProto(suite *suite.Suite, request *http.Request) *prefabProto.TelemetryEvents {
 	body, err := io.ReadAll(request.Body)
 	suite.Require().NoError(err, "failed to read request body")
@@ -42,6 +42,15 @@ func (p *LocalConfigYamlParser) handleMapKeyValue(keyPath []string, mapKey strin
 	switch value := mapValue.(type) {
 	case map[string]interface{}:
 		{
+			if decryptWith, encrypted := value["decrypt_with"]; encrypted {
+				newConfig, err := p.createEncryptedConfig(strings.Join(append(keyPath, mapKey), "."), value["value"], decryptWith)
+				if err != nil {
+					return nil, err
+				}
+
+				return []*prefabProto.Config{newConfig}, nil
+			}
+
 			featureFlagValue, featureFlagKeyExists := value["feature_flag"]
 			if featureFlagKeyExists {
 				isFeatureFlag, parsingWorked := p.coerceToBool(featureFlagValue)
@@ -108,6 +117,33 @@ func (p *LocalConfigYamlParser) handleMapKeyValue(keyPath []string, mapKey strin
 	}
 }
 
+// createEncryptedConfig creates a confidential string config from a map like
+// `{value: <data--iv--authtag>, decrypt_with: <secret key config>}`.
+func (p *LocalConfigYamlParser) createEncryptedConfig(key string, value any, decryptWith any) (*prefabProto.Config, error) {
+	encryptedValue, valueIsString := value.(string)
+	if !valueIsString {
+		return nil, fmt.Errorf("encrypted value for key %s must be a string", key)
+	}
+
+	secretKeyName, decryptWithIsString := decryptWith.(string)
+	if !decryptWithIsString || secretKeyName == "" {
+		return nil, fmt.Errorf("decrypt_with for key %s must name a config", key)
+	}
+
+	configValue := &prefabProto.ConfigValue{
+		Type:         &prefabProto.ConfigValue_String_{String_: encryptedValue},
+		DecryptWith:  &secretKeyName,
+		Confidential: BoolPtr(true),
+	}
+
+	return &prefabProto.Config{
+		Key:        key,
+		Rows:       []*prefabProto.ConfigRow{{Values: []*prefabProto.ConditionalValue{{Value: configValue}}}},
+		ValueType:  prefabProto.Config_STRING,
+		ConfigType: prefabProto.ConfigType_CONFIG,
+	}, nil
+}
+
 func (p *LocalConfigYamlParser) coerceToBool(maybeBool interface{}) (bool, bool) {
 	switch val := maybeBool.(type) {
 	case bool:
@@ -82,6 +82,25 @@ prefab:
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "encrypted value",
+			yamlInput: `
+db:
+  password: { value: "abc--def--012", decrypt_with: "secret.key" }`,
+			wantConfigs: []*prefabProto.Config{
+				s.createConfig("db.password", &prefabProto.ConfigValue{
+					Type:         &prefabProto.ConfigValue_String_{String_: "abc--def--012"},
+					DecryptWith:  internal.StringPtr("secret.key"),
+					Confidential: internal.BoolPtr(true),
+				}, prefabProto.ConfigType_CONFIG, prefabProto.Config_STRING),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:      "encrypted value without a string",
+			yamlInput: `password: { value: 12, decrypt_with: "secret.key" }`,
+			wantErr:   assert.Error,
+		},
 		{
 			name: "underscore and log level",
 			yamlInput: `
@@ -3,13 +3,17 @@ package options
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 type ConfigSource struct {
-	Store   StoreType
-	Raw     string
-	Path    string
-	Default bool
+	Store StoreType
+	Raw   string
+	Path  string
+	// Interval is how often a Poll source checks for updates. Zero means
+	// Options.PollInterval.
+	Interval time.Duration
+	Default  bool
 }
 
 type StoreType string
@@ -19,8 +23,7 @@ const (
 	DataFile   StoreType = "DataFile"
 	ConfigDump StoreType = "ConfigDump"
 	Memory     StoreType = "Memory"
-	// TODO: Support polling
-	// Poll       StoreType = "Poll"
+	Poll       StoreType = "Poll"
 
 	MemoryStoreKey = "memory://configs"
 )
@@ -48,6 +51,20 @@ func ParseConfigSource(rawSource string) (ConfigSource, error) {
 		return ConfigSource{Raw: rawSource, Store: ConfigDump, Default: false, Path: path}, nil
 	case "memory":
 		return ConfigSource{Raw: rawSource, Store: Memory, Default: false, Path: path}, nil
+	case "poll":
+		// poll:// uses the default interval, poll://30s polls every 30 seconds
+		var interval time.Duration
+
+		if path != "" {
+			var err error
+
+			interval, err = time.ParseDuration(path)
+			if err != nil || interval <= 0 {
+				return ConfigSource{}, fmt.Errorf("invalid poll interval %q in source %s", path, rawSource)
+			}
+		}
+
+		return ConfigSource{Raw: rawSource, Store: Poll, Default: false, Interval: interval}, nil
 	}
 
 	return ConfigSource{}, fmt.Errorf("unknown protocol %s", protocol)
@@ -2,6 +2,7 @@ package options_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,3 +21,18 @@ func TestGetDefaultConfigSources(t *testing.T) {
 		Default: true,
 	}, sources[0])
 }
+
+func TestParseConfigSourcePoll(t *testing.T) {
+	source, err := options.ParseConfigSource("poll://")
+	require.NoError(t, err)
+	assert.Equal(t, options.ConfigSource{Store: options.Poll, Raw: "poll://"}, source)
+
+	source, err = options.ParseConfigSource("poll://45s")
+	require.NoError(t, err)
+	assert.Equal(t, options.ConfigSource{Store: options.Poll, Raw: "poll://45s", Interval: 45 * time.Second}, source)
+
+	for _, rawSource := range []string{"poll://often", "poll://-1s", "poll://0s"} {
+		_, err = options.ParseConfigSource(rawSource)
+		assert.Error(t, err, rawSource)
+	}
+}
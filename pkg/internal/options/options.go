@@ -2,13 +2,18 @@ package options
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/connection"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/contexts"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/secrets"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/utils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
 
 // EnvLookup is an interface for looking up environment variables
@@ -32,6 +37,15 @@ const (
 	ReturnNilMatch                                // ReturnNilMatch = 1
 )
 
+// OnDisallowedValue controls what happens when a config resolves to a value
+// that isn't in its AllowableValues.
+type OnDisallowedValue int
+
+const (
+	RejectDisallowedValue OnDisallowedValue = iota // RejectDisallowedValue = 0
+	UseFirstAllowedValue                           // UseFirstAllowedValue = 1
+)
+
 const (
 	// #nosec G101 -- This is just the env var name
 	APIKeyEnvVar = "PREFAB_API_KEY"
@@ -67,15 +81,36 @@ type Options struct {
 	ProjectEnvID                 int64
 	InitializationTimeoutSeconds float64
 	OnInitializationFailure      OnInitializationFailure
+	OnDisallowedValue            OnDisallowedValue
 	ContextTelemetryMode         ContextTelemetryMode
 	CollectEvaluationSummaries   bool
+	CollectLoggerCounts          bool
 	TelemetrySyncInterval        time.Duration
 	TelemetryHost                string
 	InstanceHash                 string
 	CustomEnvLookup              EnvLookup
+	ProvidedResolver             utils.ProvidedResolver
+	SecretKeyProvider            secrets.SecretKeyProvider
+	LastKnownGoodPath            string
+	PollInterval                 time.Duration
+	SSEFailuresBeforePolling     int
+	StreamRetryInterval          time.Duration
+	MaxStaleness                 time.Duration
+	HTTPClient                   *http.Client
+	HTTPTransport                http.RoundTripper
+	RefetchWhenStale             bool
+	OnUnknownOperator            func(criterion *prefabProto.Criterion)
+	OnStateChange                func(change connection.StateChange)
+	CustomOperators              map[prefabProto.Criterion_CriterionOperator]func(criterion *prefabProto.Criterion, contextValue any, contextValueExists bool) bool
 }
 
-const timeoutDefault = 10.0
+const (
+	timeoutDefault                  = 10.0
+	pollIntervalDefault             = 30 * time.Second
+	sseFailuresBeforePollingDefault = 5
+	streamRetryIntervalDefault      = 5 * time.Minute
+	httpTimeoutDefault              = 30 * time.Second
+)
 
 func GetDefaultOptions() Options {
 	var apiURLs []string
@@ -102,6 +137,7 @@ func GetDefaultOptions() Options {
 		APIURLs:                      apiURLs,
 		InitializationTimeoutSeconds: timeoutDefault,
 		OnInitializationFailure:      ReturnError,
+		OnDisallowedValue:            RejectDisallowedValue,
 		GlobalContext:                contexts.NewContextSet(),
 		Sources:                      sources,
 		ContextTelemetryMode:         ContextTelemetryModes.PeriodicExample,
@@ -110,11 +146,45 @@ func GetDefaultOptions() Options {
 		CollectEvaluationSummaries:   true,
 		InstanceHash:                 uuid.New().String(),
 		CustomEnvLookup:              &RealEnvLookup{},
+		PollInterval:                 pollIntervalDefault,
+		SSEFailuresBeforePolling:     sseFailuresBeforePollingDefault,
+		StreamRetryInterval:          streamRetryIntervalDefault,
+	}
+}
+
+// HTTPClientOrDefault returns the client for API and telemetry requests:
+// HTTPClient (or a new client, if it's unset) using HTTPTransport, if set. A
+// client without a Timeout gets a default one so a stuck request can't hang a
+// load or a poll forever.
+func (o *Options) HTTPClientOrDefault() *http.Client {
+	client := &http.Client{}
+	if o.HTTPClient != nil {
+		// copy it so the caller's client isn't changed
+		*client = *o.HTTPClient
 	}
+
+	if o.HTTPTransport != nil {
+		client.Transport = o.HTTPTransport
+	}
+
+	if client.Timeout == 0 {
+		client.Timeout = httpTimeoutDefault
+	}
+
+	return client
+}
+
+// StreamingHTTPClient returns the client for the SSE stream. It is
+// HTTPClientOrDefault without a Timeout, which would cut off the stream.
+func (o *Options) StreamingHTTPClient() *http.Client {
+	client := o.HTTPClientOrDefault()
+	client.Timeout = 0
+
+	return client
 }
 
 func (o *Options) TelemetryEnabled() bool {
-	return o.CollectEvaluationSummaries || o.ContextTelemetryMode != ContextTelemetryModes.None
+	return o.CollectEvaluationSummaries || o.CollectLoggerCounts || o.ContextTelemetryMode != ContextTelemetryModes.None
 }
 
 func (o *Options) APIKeySettingOrEnvVar() (string, error) {
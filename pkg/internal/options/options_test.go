@@ -1,7 +1,9 @@
 package options_test
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
 	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
 
@@ -60,4 +62,41 @@ func TestOptions_TelemetryEnabledCalculatesBasedOnOptions(t *testing.T) {
 
 	_ = prefab.WithContextTelemetryMode(options.ContextTelemetryModes.None)(&defaultOptions)
 	assert.False(t, defaultOptions.TelemetryEnabled())
+
+	_ = prefab.WithCollectLoggerCounts(true)(&defaultOptions)
+	assert.True(t, defaultOptions.TelemetryEnabled())
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestOptions_HTTPClientOrDefault(t *testing.T) {
+	o := options.Options{}
+
+	client := o.HTTPClientOrDefault()
+	assert.Nil(t, client.Transport, "the default transport is used")
+	assert.Equal(t, 30*time.Second, client.Timeout, "requests time out by default")
+
+	base := &http.Client{Timeout: 5 * time.Second}
+	transport := roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+
+	o = options.Options{HTTPClient: base, HTTPTransport: transport}
+
+	client = o.HTTPClientOrDefault()
+	assert.NotSame(t, base, client)
+	assert.Nil(t, base.Transport, "the given client isn't changed")
+	assert.NotNil(t, client.Transport)
+	assert.Equal(t, 5*time.Second, client.Timeout)
+
+	streaming := o.StreamingHTTPClient()
+	assert.NotNil(t, streaming.Transport)
+	assert.Zero(t, streaming.Timeout, "a timeout would cut off the stream")
+	assert.Equal(t, 5*time.Second, base.Timeout)
+
+	o = options.Options{HTTPClient: &http.Client{}}
+	assert.Equal(t, 30*time.Second, o.HTTPClientOrDefault().Timeout, "a client without a timeout gets the default")
+	assert.Zero(t, o.StreamingHTTPClient().Timeout)
 }
@@ -0,0 +1,194 @@
+// Package ratelimit enforces limits in-process with token buckets.
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrInvalidAmount is returned by Acquire when asked for fewer than one permit.
+var ErrInvalidAmount = errors.New("ratelimit: amount must be positive")
+
+// Policy allows Limit permits per Window, with up to Burst (or Limit, if
+// Burst isn't set) available at once. A zero Window never refills, so Limit
+// is a lifetime quota.
+type Policy struct {
+	Window time.Duration
+	Limit  int32
+	Burst  int32
+}
+
+func (p Policy) capacity() float64 {
+	if p.Burst > 0 {
+		return float64(p.Burst)
+	}
+
+	return float64(p.Limit)
+}
+
+// ratePerSecond is how quickly tokens are refilled.
+func (p Policy) ratePerSecond() float64 {
+	if p.Window <= 0 {
+		return 0
+	}
+
+	return float64(p.Limit) / p.Window.Seconds()
+}
+
+// Result describes the outcome of Acquire. EnforcedGroup, Remaining and
+// ResetAt describe the bucket that rejected the request, or the one with the
+// fewest remaining permits if it passed. ResetAt is zero for policies that
+// never refill.
+type Result struct {
+	ResetAt       time.Time
+	EnforcedGroup string
+	RetryAfter    time.Duration
+	Remaining     int64
+	Passed        bool
+}
+
+type bucket struct {
+	updatedAt time.Time
+	policy    Policy
+	tokens    float64
+}
+
+func (b *bucket) refill(now time.Time, policy Policy) {
+	if b.policy != policy {
+		// The definition changed; keep what's left, within the new capacity.
+		b.policy = policy
+		b.tokens = math.Min(b.tokens, policy.capacity())
+	}
+
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(policy.capacity(), b.tokens+elapsed*policy.ratePerSecond())
+	}
+
+	b.updatedAt = now
+}
+
+// timeUntil returns how long until the bucket holds amount tokens, or false
+// if it never will.
+func (b *bucket) timeUntil(amount float64) (time.Duration, bool) {
+	if b.tokens >= amount {
+		return 0, true
+	}
+
+	rate := b.policy.ratePerSecond()
+	if rate == 0 || amount > b.policy.capacity() {
+		return 0, false
+	}
+
+	return time.Duration((amount - b.tokens) / rate * float64(time.Second)), true
+}
+
+// sweepInterval is how many calls to Acquire happen between removals of
+// buckets that have refilled completely.
+const sweepInterval = 1024
+
+// Limiter holds a token bucket per group.
+type Limiter struct {
+	buckets      map[string]*bucket
+	now          func() time.Time
+	acquisitions int
+	mutex        sync.Mutex
+}
+
+// New creates a Limiter.
+func New() *Limiter {
+	return NewWithClock(time.Now)
+}
+
+// NewWithClock creates a Limiter that reads the time from now.
+func NewWithClock(now func() time.Time) *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket), now: now}
+}
+
+// Acquire takes amount permits from the bucket of every group under policy.
+// Permits are only taken if every group has enough, so a rejected request
+// doesn't use up any of its groups' limits. amount must be positive.
+func (l *Limiter) Acquire(policy Policy, groups []string, amount int32) (Result, error) {
+	if amount <= 0 {
+		return Result{}, ErrInvalidAmount
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+
+	l.acquisitions++
+	if l.acquisitions%sweepInterval == 0 {
+		l.sweep(now)
+	}
+
+	buckets := make([]*bucket, len(groups))
+
+	for i, group := range groups {
+		b, ok := l.buckets[group]
+		if !ok {
+			b = &bucket{policy: policy, tokens: policy.capacity(), updatedAt: now}
+			l.buckets[group] = b
+		}
+
+		b.refill(now, policy)
+		buckets[i] = b
+	}
+
+	for i, b := range buckets {
+		if b.tokens < float64(amount) {
+			result := l.result(groups[i], b, now)
+			if wait, ok := b.timeUntil(float64(amount)); ok {
+				result.RetryAfter = wait
+			}
+
+			return result, nil
+		}
+	}
+
+	enforced := -1
+
+	for i, b := range buckets {
+		b.tokens -= float64(amount)
+
+		if enforced < 0 || b.tokens < buckets[enforced].tokens {
+			enforced = i
+		}
+	}
+
+	if enforced < 0 {
+		return Result{Passed: true}, nil
+	}
+
+	result := l.result(groups[enforced], buckets[enforced], now)
+	result.Passed = true
+
+	return result, nil
+}
+
+func (l *Limiter) result(group string, b *bucket, now time.Time) Result {
+	result := Result{
+		EnforcedGroup: group,
+		Remaining:     int64(math.Floor(b.tokens)),
+	}
+
+	if untilFull, ok := b.timeUntil(b.policy.capacity()); ok {
+		result.ResetAt = now.Add(untilFull)
+	}
+
+	return result
+}
+
+// sweep removes buckets that are full again; a new bucket behaves the same.
+func (l *Limiter) sweep(now time.Time) {
+	for group, b := range l.buckets {
+		b.refill(now, b.policy)
+
+		if b.tokens >= b.policy.capacity() {
+			delete(l.buckets, group)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/ratelimit"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func acquire(t *testing.T, limiter *ratelimit.Limiter, policy ratelimit.Policy, groups []string, amount int32) ratelimit.Result {
+	t.Helper()
+
+	result, err := limiter.Acquire(policy, groups, amount)
+	require.NoError(t, err)
+
+	return result
+}
+
+func TestAcquireRefillsOverTheWindow(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	limiter := ratelimit.NewWithClock(clock.Now)
+	policy := ratelimit.Policy{Limit: 60, Window: time.Minute}
+
+	result := acquire(t, limiter, policy, []string{"a"}, 60)
+	assert.True(t, result.Passed)
+	assert.Equal(t, int64(0), result.Remaining)
+	assert.Equal(t, clock.now.Add(time.Minute), result.ResetAt)
+
+	result = acquire(t, limiter, policy, []string{"a"}, 1)
+	assert.False(t, result.Passed)
+	assert.Equal(t, "a", result.EnforcedGroup)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	clock.Advance(2 * time.Second)
+
+	result = acquire(t, limiter, policy, []string{"a"}, 2)
+	assert.True(t, result.Passed)
+
+	assert.True(t, acquire(t, limiter, policy, []string{"b"}, 1).Passed, "groups have their own buckets")
+}
+
+func TestAcquireBurst(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	limiter := ratelimit.NewWithClock(clock.Now)
+	policy := ratelimit.Policy{Limit: 10, Burst: 2, Window: time.Second}
+
+	assert.True(t, acquire(t, limiter, policy, []string{"a"}, 2).Passed)
+	assert.False(t, acquire(t, limiter, policy, []string{"a"}, 1).Passed)
+
+	clock.Advance(time.Hour)
+
+	assert.False(t, acquire(t, limiter, policy, []string{"a"}, 3).Passed, "never holds more than the burst")
+	assert.True(t, acquire(t, limiter, policy, []string{"a"}, 2).Passed)
+}
+
+func TestAcquireAllGroupsMustPass(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	limiter := ratelimit.NewWithClock(clock.Now)
+	policy := ratelimit.Policy{Limit: 3, Window: time.Hour}
+
+	assert.True(t, acquire(t, limiter, policy, []string{"user"}, 3).Passed)
+
+	result := acquire(t, limiter, policy, []string{"account", "user"}, 1)
+	assert.False(t, result.Passed)
+	assert.Equal(t, "user", result.EnforcedGroup)
+
+	result = acquire(t, limiter, policy, []string{"account"}, 1)
+	assert.True(t, result.Passed)
+	assert.Equal(t, int64(2), result.Remaining, "the rejected request didn't take from account")
+}
+
+func TestAcquireWithoutWindowNeverRefills(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	limiter := ratelimit.NewWithClock(clock.Now)
+	policy := ratelimit.Policy{Limit: 1}
+
+	result := acquire(t, limiter, policy, []string{"a"}, 1)
+	assert.True(t, result.Passed)
+	assert.True(t, result.ResetAt.IsZero())
+
+	clock.Advance(24 * time.Hour)
+
+	result = acquire(t, limiter, policy, []string{"a"}, 1)
+	assert.False(t, result.Passed)
+	assert.Zero(t, result.RetryAfter)
+}
+
+func TestAcquireAppliesChangedPolicies(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	limiter := ratelimit.NewWithClock(clock.Now)
+
+	assert.True(t, acquire(t, limiter, ratelimit.Policy{Limit: 10, Window: time.Hour}, []string{"a"}, 5).Passed)
+
+	result := acquire(t, limiter, ratelimit.Policy{Limit: 2, Window: time.Hour}, []string{"a"}, 1)
+	assert.True(t, result.Passed)
+	assert.Equal(t, int64(1), result.Remaining, "tokens are capped at the new limit")
+}
+
+func TestAcquireRejectsNonPositiveAmounts(t *testing.T) {
+	limiter := ratelimit.New()
+	policy := ratelimit.Policy{Limit: 1, Window: time.Hour}
+
+	for _, amount := range []int32{0, -1} {
+		_, err := limiter.Acquire(policy, []string{"a"}, amount)
+		require.ErrorIs(t, err, ratelimit.ErrInvalidAmount)
+	}
+
+	assert.True(t, acquire(t, limiter, policy, []string{"a"}, 1).Passed, "rejected amounts don't take permits")
+}
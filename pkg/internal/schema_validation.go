@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// ErrSchemaValidation is matched (via errors.Is) by *SchemaValidationError.
+var ErrSchemaValidation = errors.New("value does not match schema")
+
+// SchemaValidationError reports that a JSON value of Key doesn't match the
+// schema in SchemaKey.
+type SchemaValidationError struct {
+	Err       error
+	Key       string
+	SchemaKey string
+}
+
+func (e *SchemaValidationError) Error() string {
+	return fmt.Sprintf("config %q does not match schema %q: %v", e.Key, e.SchemaKey, e.Err)
+}
+
+func (e *SchemaValidationError) Is(target error) bool {
+	return target == ErrSchemaValidation
+}
+
+func (e *SchemaValidationError) Unwrap() error {
+	return e.Err
+}
+
+// SchemaValidator validates JSON config values against JSON_SCHEMA schemas,
+// caching each compiled schema. Other schema types (e.g. ZOD) can't be
+// checked in Go and always pass.
+type SchemaValidator struct {
+	compiled map[string]*jsonschema.Schema
+	mutex    sync.Mutex
+}
+
+func NewSchemaValidator() *SchemaValidator {
+	return &SchemaValidator{compiled: make(map[string]*jsonschema.Schema)}
+}
+
+// Validate checks value against schema. Values that aren't JSON pass.
+func (v *SchemaValidator) Validate(schema *prefabProto.Schema, value *prefabProto.ConfigValue) error {
+	jsonValue, isJSON := value.GetType().(*prefabProto.ConfigValue_Json)
+	if !isJSON || schema.GetSchemaType() != prefabProto.Schema_JSON_SCHEMA {
+		return nil
+	}
+
+	compiled, err := v.compile(schema.GetSchema())
+	if err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+
+	instance, err := jsonschema.UnmarshalJSON(strings.NewReader(jsonValue.Json.GetJson()))
+	if err != nil {
+		return err
+	}
+
+	return compiled.Validate(instance)
+}
+
+// ValidateConfig checks every JSON value config can produce, including
+// weighted values, against schema.
+func (v *SchemaValidator) ValidateConfig(config *prefabProto.Config, schema *prefabProto.Schema) error {
+	for _, row := range config.GetRows() {
+		for _, conditionalValue := range row.GetValues() {
+			values := []*prefabProto.ConfigValue{conditionalValue.GetValue()}
+			for _, weightedValue := range conditionalValue.GetValue().GetWeightedValues().GetWeightedValues() {
+				values = append(values, weightedValue.GetValue())
+			}
+
+			for _, value := range values {
+				if err := v.Validate(schema, value); err != nil {
+					return &SchemaValidationError{Key: config.GetKey(), SchemaKey: config.GetSchemaKey(), Err: err}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *SchemaValidator) compile(schema string) (*jsonschema.Schema, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if compiled, ok := v.compiled[schema]; ok {
+		return compiled, nil
+	}
+
+	document, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", document); err != nil {
+		return nil, err
+	}
+
+	compiled, err := compiler.Compile("schema.json")
+	if err != nil {
+		return nil, err
+	}
+
+	v.compiled[schema] = compiled
+
+	return compiled, nil
+}
+
+// SchemaFromConfig returns the first schema value in schemaConfig, preferring
+// the row for envID, or nil if there isn't one.
+func SchemaFromConfig(schemaConfig *prefabProto.Config, envID int64) *prefabProto.Schema {
+	rows := []*prefabProto.ConfigRow{}
+	if row, ok := rowWithMatchingEnvID(schemaConfig, envID); ok {
+		rows = append(rows, row)
+	}
+
+	if row, ok := rowWithoutEnvID(schemaConfig); ok {
+		rows = append(rows, row)
+	}
+
+	for _, row := range rows {
+		for _, conditionalValue := range row.GetValues() {
+			if schema := conditionalValue.GetValue().GetSchema(); schema != nil {
+				return schema
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package internal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func jsonConfigValue(json string) *prefabProto.ConfigValue {
+	return &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Json{Json: &prefabProto.Json{Json: json}}}
+}
+
+func schemaConfigValue(schema string, schemaType prefabProto.Schema_SchemaType) *prefabProto.ConfigValue {
+	return &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Schema{Schema: &prefabProto.Schema{Schema: schema, SchemaType: schemaType}}}
+}
+
+func TestSchemaValidatorValidate(t *testing.T) {
+	validator := internal.NewSchemaValidator()
+	schema := schemaConfigValue(`{"type": "object", "properties": {"retries": {"type": "integer", "maximum": 5}}, "required": ["retries"]}`, prefabProto.Schema_JSON_SCHEMA).GetSchema()
+
+	tests := []struct {
+		name    string
+		schema  *prefabProto.Schema
+		value   *prefabProto.ConfigValue
+		isValid bool
+	}{
+		{"valid", schema, jsonConfigValue(`{"retries": 3}`), true},
+		{"missing property", schema, jsonConfigValue(`{}`), false},
+		{"out of range", schema, jsonConfigValue(`{"retries": 10}`), false},
+		{"malformed json", schema, jsonConfigValue(`{"retries":`), false},
+		{"not json", schema, testutils.CreateConfigValueAndAssertOk(t, "text"), true},
+		{"zod schemas are not checked", schemaConfigValue(`z.object({})`, prefabProto.Schema_ZOD).GetSchema(), jsonConfigValue(`[]`), true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validator.Validate(testCase.schema, testCase.value)
+			if testCase.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	err := validator.Validate(schemaConfigValue(`{"type": 12}`, prefabProto.Schema_JSON_SCHEMA).GetSchema(), jsonConfigValue(`{}`))
+	assert.ErrorContains(t, err, "invalid schema")
+}
+
+func TestSchemaValidatorValidateConfig(t *testing.T) {
+	validator := internal.NewSchemaValidator()
+	schema := schemaConfigValue(`{"type": "array"}`, prefabProto.Schema_JSON_SCHEMA).GetSchema()
+
+	config := &prefabProto.Config{
+		Key:       "lists",
+		SchemaKey: internal.StringPtr("lists-schema"),
+		Rows: []*prefabProto.ConfigRow{{Values: []*prefabProto.ConditionalValue{
+			{Value: jsonConfigValue(`[1]`)},
+			{Value: &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_WeightedValues{WeightedValues: &prefabProto.WeightedValues{
+				WeightedValues: []*prefabProto.WeightedValue{
+					{Weight: 1, Value: jsonConfigValue(`[2]`)},
+					{Weight: 1, Value: jsonConfigValue(`{"not": "a list"}`)},
+				},
+			}}}},
+		}}},
+	}
+
+	err := validator.ValidateConfig(config, schema)
+	require.ErrorIs(t, err, internal.ErrSchemaValidation)
+
+	var validationErr *internal.SchemaValidationError
+
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "lists", validationErr.Key)
+	assert.Equal(t, "lists-schema", validationErr.SchemaKey)
+
+	assert.NoError(t, validator.ValidateConfig(config, nil), "nothing to check without a schema")
+}
+
+func TestSchemaFromConfig(t *testing.T) {
+	defaultSchema := schemaConfigValue(`{"type": "object"}`, prefabProto.Schema_JSON_SCHEMA)
+	envSchema := schemaConfigValue(`{"type": "array"}`, prefabProto.Schema_JSON_SCHEMA)
+
+	config := &prefabProto.Config{
+		Key: "schema",
+		Rows: []*prefabProto.ConfigRow{
+			{Values: []*prefabProto.ConditionalValue{{Value: defaultSchema}}},
+			{ProjectEnvId: internal.Int64Ptr(101), Values: []*prefabProto.ConditionalValue{{Value: envSchema}}},
+		},
+	}
+
+	assert.Equal(t, envSchema.GetSchema(), internal.SchemaFromConfig(config, 101))
+	assert.Equal(t, defaultSchema.GetSchema(), internal.SchemaFromConfig(config, 5))
+	assert.Nil(t, internal.SchemaFromConfig(&prefabProto.Config{}, 101))
+}
@@ -0,0 +1,90 @@
+// Package secrets finds the keys used to decrypt encrypted (decrypt_with)
+// config values.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrSecretKeyNotFound is returned by a SecretKeyProvider that doesn't know a
+// key. The client then falls back to evaluating the decrypt_with config.
+var ErrSecretKeyNotFound = errors.New("secret key not found")
+
+// SecretKeyProvider returns the keys for name, the key of the config named in
+// an encrypted value's decrypt_with. Several keys can be returned while a key
+// is being rotated; they are tried in order.
+type SecretKeyProvider interface {
+	SecretKeys(name string) ([]string, error)
+}
+
+// SecretKeyProviderFunc adapts a function (e.g. a call to a KMS) to a
+// SecretKeyProvider.
+type SecretKeyProviderFunc func(name string) ([]string, error)
+
+func (f SecretKeyProviderFunc) SecretKeys(name string) ([]string, error) {
+	return f(name)
+}
+
+// EnvSecretKeyProvider reads the keys for each name from the environment
+// variables listed in EnvVars, using LookupEnv (or os.LookupEnv if it is nil).
+// Variables that aren't set are skipped.
+type EnvSecretKeyProvider struct {
+	LookupEnv func(key string) (string, bool)
+	EnvVars   map[string][]string
+}
+
+func (p EnvSecretKeyProvider) SecretKeys(name string) ([]string, error) {
+	lookupEnv := p.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	var keys []string
+
+	for _, envVar := range p.EnvVars[name] {
+		if key, exists := lookupEnv(envVar); exists && key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSecretKeyNotFound, name)
+	}
+
+	return keys, nil
+}
+
+// FileSecretKeyProvider reads the keys for each name from the files listed in
+// Files, such as secrets mounted under /run/secrets. Missing files are skipped
+// and trailing newlines are trimmed.
+type FileSecretKeyProvider struct {
+	Files map[string][]string
+}
+
+func (p FileSecretKeyProvider) SecretKeys(name string) ([]string, error) {
+	var keys []string
+
+	for _, path := range p.Files[name] {
+		contents, err := os.ReadFile(path)
+
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		if key := strings.TrimRight(string(contents), "\r\n"); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSecretKeyNotFound, name)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,73 @@
+package secrets_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/secrets"
+)
+
+func TestEnvSecretKeyProvider(t *testing.T) {
+	provider := secrets.EnvSecretKeyProvider{
+		LookupEnv: func(key string) (string, bool) {
+			value, exists := map[string]string{"NEW_KEY": "new", "OLD_KEY": "old"}[key]
+
+			return value, exists
+		},
+		EnvVars: map[string][]string{
+			"rotating": {"NEW_KEY", "UNSET_KEY", "OLD_KEY"},
+			"unset":    {"UNSET_KEY"},
+		},
+	}
+
+	keys, err := provider.SecretKeys("rotating")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new", "old"}, keys)
+
+	_, err = provider.SecretKeys("unset")
+	require.ErrorIs(t, err, secrets.ErrSecretKeyNotFound)
+
+	_, err = provider.SecretKeys("unknown")
+	require.ErrorIs(t, err, secrets.ErrSecretKeyNotFound)
+}
+
+func TestFileSecretKeyProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key"), []byte("from-a-file\n"), 0o600))
+
+	provider := secrets.FileSecretKeyProvider{Files: map[string][]string{
+		"mounted": {filepath.Join(dir, "missing"), filepath.Join(dir, "key")},
+		"missing": {filepath.Join(dir, "missing")},
+	}}
+
+	keys, err := provider.SecretKeys("mounted")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"from-a-file"}, keys)
+
+	_, err = provider.SecretKeys("missing")
+	require.ErrorIs(t, err, secrets.ErrSecretKeyNotFound)
+}
+
+func TestSecretKeyProviderFunc(t *testing.T) {
+	kmsUnavailable := errors.New("kms unavailable")
+
+	provider := secrets.SecretKeyProviderFunc(func(name string) ([]string, error) {
+		if name == "kms" {
+			return nil, kmsUnavailable
+		}
+
+		return []string{name + "-key"}, nil
+	})
+
+	keys, err := provider.SecretKeys("app")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app-key"}, keys)
+
+	_, err = provider.SecretKeys("kms")
+	require.ErrorIs(t, err, kmsUnavailable)
+}
@@ -1,24 +1,51 @@
 package sse
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"log/slog"
+	"math/rand/v2"
 	"regexp"
+	"slices"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	sse "github.com/r3labs/sse/v2"
 	"google.golang.org/protobuf/proto"
+	"gopkg.in/cenkalti/backoff.v1"
 
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/connection"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
 	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
 
+const (
+	defaultMinReconnectDelay = 1 * time.Second
+	defaultMaxReconnectDelay = 30 * time.Second
+	// The API sends a keep-alive every few seconds, so a connection that's
+	// quiet for this long has died without being closed.
+	defaultKeepAliveTimeout = 90 * time.Second
+)
+
 var subdomainRegex = regexp.MustCompile(`(belt|suspenders)\.`)
 
-func BuildSSEClient(options options.Options) (*sse.Client, error) {
+// ErrStreamUnavailable is returned by Stream.Start when it gives up on the
+// config stream.
+var ErrStreamUnavailable = errors.New("sse: config stream is unavailable")
+
+var errKeepAliveTimeout = errors.New("sse: connection went quiet")
+
+type ConfigStore interface {
+	SetFromConfigsProto(configs *prefabProto.Configs)
+	GetHighWatermark() int64
+}
+
+// BuildSSEClients returns a client for the config stream of each API URL.
+// URLs that share a stream (like belt and suspenders) get a single client.
+func BuildSSEClients(options options.Options) ([]*sse.Client, error) {
 	apiURLs, err := options.PrefabAPIURLEnvVarOrSetting()
 	if err != nil {
 		return nil, err
@@ -29,65 +56,260 @@ func BuildSSEClient(options options.Options) (*sse.Client, error) {
 	}
 
 	authString := base64.StdEncoding.EncodeToString([]byte("authuser:" + options.APIKey))
+	httpClient := options.StreamingHTTPClient()
+
+	var (
+		clients    []*sse.Client
+		streamURLs []string
+	)
+
+	for _, apiURL := range apiURLs {
+		url := replaceFirstOccurrence(apiURL, subdomainRegex, "stream.") + "/api/v1/sse/config"
+		if slices.Contains(streamURLs, url) {
+			continue
+		}
+
+		streamURLs = append(streamURLs, url)
+
+		client := sse.NewClient(url)
+		client.Connection = httpClient
+		client.Headers = map[string]string{
+			"Authorization":                "Basic " + authString,
+			"X-PrefabCloud-Client-Version": internal.ClientVersionHeader,
+			"Accept":                       "text/event-stream",
+		}
+		// Stream does its own reconnecting so it can move on to another URL.
+		client.ReconnectStrategy = &backoff.StopBackOff{}
 
-	// TODO: handle multiple api urls
-	url := replaceFirstOccurrence(apiURLs[0], subdomainRegex, "stream.") + "/api/v1/sse/config"
-	client := sse.NewClient(url)
-	client.Headers = map[string]string{
-		"Authorization":                "Basic " + authString,
-		"X-PrefabCloud-Client-Version": internal.ClientVersionHeader,
-		"Accept":                       "text/event-stream",
+		clients = append(clients, client)
 	}
 
-	return client, nil
+	return clients, nil
 }
 
-type ConfigStore interface {
-	SetFromConfigsProto(configs *prefabProto.Configs)
-	GetHighWatermark() int64
+// endpoint is one of a Stream's clients and how it has been doing lately.
+type endpoint struct {
+	unhealthyUntil time.Time
+	client         *sse.Client
+	failures       int
 }
 
-func StartSSEConnection(client *sse.Client, apiConfigStore ConfigStore) {
-	for {
-		client.Headers["x-prefab-start-at-id"] = strconv.FormatInt(apiConfigStore.GetHighWatermark(), 10)
+// Stream keeps a config stream open against one of several endpoints,
+// failing over to the next one when a connection can't be made, ends without
+// delivering anything, or goes quiet for longer than KeepAliveTimeout.
+// Endpoints that fail are avoided for an exponentially growing, jittered
+// period, as are reconnects after consecutive failures.
+type Stream struct {
+	endpoints []*endpoint
+	// Connection, if set, is told when the stream connects, drops and
+	// delivers messages.
+	Connection *connection.Tracker
+	// MaxFailures is how many connections in a row may fail before Start
+	// gives up with ErrStreamUnavailable. Zero means never give up.
+	MaxFailures       int
+	KeepAliveTimeout  time.Duration
+	MinReconnectDelay time.Duration
+	MaxReconnectDelay time.Duration
+}
 
-		err := client.Subscribe("", func(msg *sse.Event) {
-			// Skip empty events (phantom events from SSE library bug when processing comments)
-			if len(msg.Data) == 0 {
-				return
-			}
+// BuildStream returns a Stream over the config streams of options' API URLs.
+func BuildStream(options options.Options) (*Stream, error) {
+	clients, err := BuildSSEClients(options)
+	if err != nil {
+		return nil, err
+	}
 
-			decoded := make([]byte, base64.StdEncoding.DecodedLen(len(msg.Data)))
+	return NewStream(clients, options.SSEFailuresBeforePolling), nil
+}
 
-			numberOfBytesWritten, err := base64.StdEncoding.Decode(decoded, msg.Data)
-			if err != nil {
-				slog.Error("sse: error decoding base64 data", "err", err.Error())
+// NewStream returns a Stream over clients, which are tried in order.
+func NewStream(clients []*sse.Client, maxFailures int) *Stream {
+	endpoints := make([]*endpoint, 0, len(clients))
+	for _, client := range clients {
+		endpoints = append(endpoints, &endpoint{client: client})
+	}
 
-				return
-			}
+	return &Stream{
+		endpoints:         endpoints,
+		MaxFailures:       maxFailures,
+		KeepAliveTimeout:  defaultKeepAliveTimeout,
+		MinReconnectDelay: defaultMinReconnectDelay,
+		MaxReconnectDelay: defaultMaxReconnectDelay,
+	}
+}
 
-			// Trim the decoded slice to the actual length of the decoded data
-			decoded = decoded[:numberOfBytesWritten]
+// Start subscribes to the config stream and keeps reconnecting until ctx is
+// cancelled, or until MaxFailures connections in a row have failed.
+func (s *Stream) Start(ctx context.Context, apiConfigStore ConfigStore) error {
+	if len(s.endpoints) == 0 {
+		return errors.New("sse: no endpoints to stream from")
+	}
+
+	failures := 0
+	next := 0
 
-			var configs prefabProto.Configs
+	for ctx.Err() == nil {
+		current := s.pickEndpoint(next)
 
-			err = proto.Unmarshal(decoded, &configs)
-			if err != nil {
-				slog.Error("sse: error unmarshalling proto", "err", err.Error())
+		ok, err := s.subscribe(ctx, s.endpoints[current], apiConfigStore)
+		if ctx.Err() != nil {
+			break
+		}
+
+		// configs that never loaded stay Failed until the stream delivers
+		if s.Connection.Status().State != connection.Failed {
+			s.Connection.SetState(connection.Reconnecting, err)
+		}
 
-				return
+		if ok {
+			failures = 0
+			next = current
+			s.endpoints[current].failures = 0
+			s.endpoints[current].unhealthyUntil = time.Time{}
+		} else {
+			failures++
+			next = current + 1
+			s.endpoints[current].failures++
+			s.endpoints[current].unhealthyUntil = time.Now().Add(s.reconnectDelay(s.endpoints[current].failures))
+
+			if s.MaxFailures > 0 && failures >= s.MaxFailures {
+				return ErrStreamUnavailable
 			}
+		}
+
+		// Wait before reconnecting to avoid hammering the API, longer the
+		// more connections have failed in a row.
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.reconnectDelay(failures)):
+		}
+	}
+
+	return ctx.Err()
+}
+
+// pickEndpoint returns the index of the first healthy endpoint from start
+// onwards (wrapping around), or of the one that'll be healthy soonest.
+func (s *Stream) pickEndpoint(start int) int {
+	now := time.Now()
+	soonest := start % len(s.endpoints)
+
+	for offset := range s.endpoints {
+		index := (start + offset) % len(s.endpoints)
+		if !now.Before(s.endpoints[index].unhealthyUntil) {
+			return index
+		}
 
-			apiConfigStore.SetFromConfigsProto(&configs)
+		if s.endpoints[index].unhealthyUntil.Before(s.endpoints[soonest].unhealthyUntil) {
+			soonest = index
+		}
+	}
+
+	return soonest
+}
+
+// reconnectDelay is MinReconnectDelay doubled for each failure after the
+// first, capped at MaxReconnectDelay, with up to half of it taken off at
+// random so clients don't reconnect in lockstep.
+func (s *Stream) reconnectDelay(failures int) time.Duration {
+	delay := s.MinReconnectDelay
+	for i := 1; i < failures && delay < s.MaxReconnectDelay; i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, s.MaxReconnectDelay)
+	if delay <= 0 {
+		return 0
+	}
+
+	// #nosec G404 -- jitter doesn't need a secure random source
+	return delay - rand.N(delay/2+1)
+}
+
+// subscribe streams configs from endpoint into apiConfigStore until the
+// connection ends. It reports whether the connection was healthy (it
+// delivered at least one message and didn't go quiet) and why it ended.
+func (s *Stream) subscribe(ctx context.Context, endpoint *endpoint, apiConfigStore ConfigStore) (bool, error) {
+	subscriptionCtx, cancelSubscription := context.WithCancel(ctx)
+	defer cancelSubscription()
+
+	var timedOut atomic.Bool
+
+	resetWatchdog := func() {}
+
+	if s.KeepAliveTimeout > 0 {
+		watchdog := time.AfterFunc(s.KeepAliveTimeout, func() {
+			timedOut.Store(true)
+			cancelSubscription()
 		})
+		defer watchdog.Stop()
+
+		resetWatchdog = func() { watchdog.Reset(s.KeepAliveTimeout) }
+	}
+
+	client := endpoint.client
+	client.Headers["x-prefab-start-at-id"] = strconv.FormatInt(apiConfigStore.GetHighWatermark(), 10)
+
+	// The library calls the handler from this goroutine, so received needs
+	// no locking.
+	received := false
+
+	err := client.SubscribeWithContext(subscriptionCtx, "", func(msg *sse.Event) {
+		// any traffic shows the connection is still alive
+		resetWatchdog()
+
+		// Skip empty events (phantom events from SSE library bug when processing comments)
+		if len(msg.Data) == 0 {
+			return
+		}
+
+		configs, err := decodeConfigs(msg.Data)
 		if err != nil {
-			slog.Error("sse:", "err", err.Error())
+			slog.Error("sse: error decoding configs", "err", err.Error())
+
+			return
+		}
+
+		received = true
+
+		s.Connection.SetState(connection.Streaming, nil)
+
+		if !configs.GetKeepAlive() || len(configs.GetConfigs()) > 0 {
+			apiConfigStore.SetFromConfigsProto(configs)
 		}
 
-		// If we get here, the connection was closed. We should try to reconnect.
-		// We sleep for a second to avoid hammering the server.
-		time.Sleep(1 * time.Second)
+		s.Connection.Synced(apiConfigStore.GetHighWatermark())
+	})
+
+	switch {
+	case timedOut.Load():
+		slog.Warn("sse: connection went quiet, reconnecting", "url", client.URL, "timeout", s.KeepAliveTimeout)
+
+		err = errKeepAliveTimeout
+	case err != nil && subscriptionCtx.Err() == nil:
+		slog.Error("sse:", "url", client.URL, "err", err.Error())
 	}
+
+	return received && !timedOut.Load(), err
+}
+
+func decodeConfigs(data []byte) (*prefabProto.Configs, error) {
+	decoded := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+
+	numberOfBytesWritten, err := base64.StdEncoding.Decode(decoded, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs prefabProto.Configs
+
+	// Trim the decoded slice to the actual length of the decoded data
+	err = proto.Unmarshal(decoded[:numberOfBytesWritten], &configs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configs, nil
 }
 
 func replaceFirstOccurrence(s string, r *regexp.Regexp, replacement string) string {
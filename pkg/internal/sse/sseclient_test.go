@@ -1,32 +1,47 @@
 package sse_test
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	sseclient "github.com/r3labs/sse/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
 	sse "github.com/prefab-cloud/prefab-cloud-go/pkg/internal/sse"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
 
-func TestBuildSSEClient(t *testing.T) {
+func TestBuildSSEClients(t *testing.T) {
 	options := options.Options{
 		APIKey:  "does-not-matter",
-		APIURLs: []string{"https://belt.prefab.cloud"},
+		APIURLs: []string{"https://belt.prefab.cloud", "https://suspenders.prefab.cloud", "https://prefab.example.com"},
 	}
 
-	client, err := sse.BuildSSEClient(options)
+	clients, err := sse.BuildSSEClients(options)
 
-	assert.NoError(t, err)
-	assert.Equal(t, "https://stream.prefab.cloud/api/v1/sse/config", client.URL)
+	require.NoError(t, err)
+	require.Len(t, clients, 2, "belt and suspenders share a stream")
+	assert.Equal(t, "https://stream.prefab.cloud/api/v1/sse/config", clients[0].URL)
+	assert.Equal(t, "https://prefab.example.com/api/v1/sse/config", clients[1].URL)
 
-	assert.Equal(t, map[string]string{
-		"Authorization":                "Basic YXV0aHVzZXI6ZG9lcy1ub3QtbWF0dGVy",
-		"X-PrefabCloud-Client-Version": internal.ClientVersionHeader,
-		"Accept":                       "text/event-stream",
-	}, client.Headers)
+	for _, client := range clients {
+		assert.Equal(t, map[string]string{
+			"Authorization":                "Basic YXV0aHVzZXI6ZG9lcy1ub3QtbWF0dGVy",
+			"X-PrefabCloud-Client-Version": internal.ClientVersionHeader,
+			"Accept":                       "text/event-stream",
+		}, client.Headers)
+	}
 }
 
 func TestEventHandlerIgnoresEmptyEvents(t *testing.T) {
@@ -73,3 +88,154 @@ func (m *mockConfigStore) SetFromConfigsProto(configs interface{}) {
 func (m *mockConfigStore) GetHighWatermark() int64 {
 	return m.highWatermark
 }
+
+type recordingConfigStore struct {
+	configs []*prefabProto.Configs
+	sync.Mutex
+}
+
+func (r *recordingConfigStore) SetFromConfigsProto(configs *prefabProto.Configs) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.configs = append(r.configs, configs)
+}
+
+func (r *recordingConfigStore) GetHighWatermark() int64 {
+	return 0
+}
+
+func (r *recordingConfigStore) count() int {
+	r.Lock()
+	defer r.Unlock()
+
+	return len(r.configs)
+}
+
+// streamServer serves the config stream, sending each of messages and then
+// either closing the connection or, if hang is set, leaving it open.
+func streamServer(t *testing.T, requests *atomic.Int32, hang bool, messages ...*prefabProto.Configs) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		for _, message := range messages {
+			body, err := proto.Marshal(message)
+			if err != nil {
+				return
+			}
+
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", base64.StdEncoding.EncodeToString(body))
+		}
+
+		w.(http.Flusher).Flush()
+
+		if hang {
+			<-r.Context().Done()
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func forbiddenServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+
+		// a proxy that won't pass event streams through
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newTestStream(maxFailures int, urls ...string) *sse.Stream {
+	clients, _ := sse.BuildSSEClients(options.Options{APIKey: "does-not-matter", APIURLs: urls})
+
+	stream := sse.NewStream(clients, maxFailures)
+	stream.MinReconnectDelay = 10 * time.Millisecond
+	stream.MaxReconnectDelay = 40 * time.Millisecond
+
+	return stream
+}
+
+func TestStreamGivesUpAfterMaxFailures(t *testing.T) {
+	var requests atomic.Int32
+
+	server := forbiddenServer(t, &requests)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := newTestStream(3, server.URL).Start(ctx, &recordingConfigStore{})
+
+	require.ErrorIs(t, err, sse.ErrStreamUnavailable)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestStreamStopsWhenCancelled(t *testing.T) {
+	var requests atomic.Int32
+
+	server := forbiddenServer(t, &requests)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	err := newTestStream(0, server.URL).Start(ctx, &recordingConfigStore{})
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestStreamFailsOverToHealthyURL(t *testing.T) {
+	var badRequests, goodRequests atomic.Int32
+
+	bad := forbiddenServer(t, &badRequests)
+	good := streamServer(t, &goodRequests, false, &prefabProto.Configs{
+		Configs: []*prefabProto.Config{{Key: "foo", Id: 1}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := &recordingConfigStore{}
+	done := make(chan error)
+
+	go func() { done <- newTestStream(0, bad.URL, good.URL).Start(ctx, store) }()
+
+	assert.Eventually(t, func() bool { return store.count() >= 3 }, 5*time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	// once the good URL has worked, reconnects stay with it
+	assert.Equal(t, int32(1), badRequests.Load())
+	assert.GreaterOrEqual(t, goodRequests.Load(), int32(3))
+}
+
+func TestStreamReconnectsWhenKeepAlivesStop(t *testing.T) {
+	var requests atomic.Int32
+
+	server := streamServer(t, &requests, true, &prefabProto.Configs{KeepAlive: proto.Bool(true)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	store := &recordingConfigStore{}
+
+	stream := newTestStream(2, server.URL)
+	stream.KeepAliveTimeout = 50 * time.Millisecond
+
+	err := stream.Start(ctx, store)
+
+	require.ErrorIs(t, err, sse.ErrStreamUnavailable, "quiet connections count as failures")
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Zero(t, store.count(), "keep-alives aren't passed on to the store")
+}
@@ -1,86 +1,555 @@
 package stores
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"os"
+	"sort"
 	"sync"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+
+	prefabInternalProto "github.com/prefab-cloud/prefab-cloud-go/internal-proto"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/connection"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/contexts"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/sse"
 	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
 
-const maxRetries = 10
+// fetchRetryDelay is how much longer each retry of the initial fetch waits
+// than the one before.
+var fetchRetryDelay = time.Second
+
+const (
+	maxRetries          = 10
+	defaultPollInterval = 30 * time.Second
+	// defaultStreamRetryInterval is how long the store polls before trying a
+	// failed stream again.
+	defaultStreamRetryInterval = 5 * time.Minute
+)
 
 type APIConfigStore struct {
 	configMap       map[string]*prefabProto.Config
 	contextSet      *contexts.ContextSet
+	defaultContext  *prefabProto.ContextSet
 	httpClient      *internal.HTTPClient
 	finishedLoading func()
-	highWatermark   int64
-	projectEnvID    int64
+	notifier        *internal.ConfigChangeNotifier
+	connection      *connection.Tracker
+	schemaValidator *internal.SchemaValidator
+	// lastKnownGoodPath, if set, is where every load and update is saved as
+	// a ConfigDump (with its metadata beside it, see lastKnownGoodMetaPath),
+	// and where the store starts from on the next run.
+	lastKnownGoodPath string
+	// apiKeyID identifies the API key without revealing it, so a saved file
+	// is only used with the key it was saved for.
+	apiKeyID           string
+	lastKnownGoodMutex sync.Mutex
+	ctx                context.Context
+	cancel             context.CancelFunc
+	workers            sync.WaitGroup
+	highWatermark      int64
+	projectEnvID       int64
 	sync.RWMutex
 	Initialized bool
 }
 
-func NewAPIConfigStore(options options.Options, finishedLoading func()) (*APIConfigStore, error) {
-	httpClient, err := internal.BuildHTTPClient(options)
+// NewAPIConfigStore starts loading configs from the API and streaming updates
+// over SSE. If the stream keeps failing (see
+// options.SSEFailuresBeforePolling), the store polls for updates instead,
+// trying the stream again every options.StreamRetryInterval and pausing the
+// polls while it delivers. Every change applied to the store is reported to
+// notifier, and the state of its connection to tracker; both may be nil.
+func NewAPIConfigStore(options options.Options, finishedLoading func(), notifier *internal.ConfigChangeNotifier, tracker *connection.Tracker) (*APIConfigStore, error) {
+	stream, err := sse.BuildStream(options)
 	if err != nil {
 		panic(err)
 	}
 
-	sseClient, err := sse.BuildSSEClient(options)
+	store := newAPIConfigStore(options, finishedLoading, notifier, tracker)
+
+	stream.Connection = store.connection
+
+	store.start(func() {
+		err := stream.Start(store.ctx, store)
+		if !errors.Is(err, sse.ErrStreamUnavailable) {
+			return
+		}
+
+		interval := pollInterval(options, 0)
+		retryInterval := streamRetryInterval(options)
+
+		// poll sets the state to Loaded once it gets configs, so a store
+		// that never loaded any stays Failed
+		slog.Warn("config stream keeps failing, polling for updates until it recovers", "interval", interval, "streamRetryInterval", retryInterval)
+
+		store.workers.Add(1)
+
+		go func() {
+			defer store.workers.Done()
+
+			store.poll(interval)
+		}()
+
+		store.retryStream(stream, retryInterval)
+	})
+
+	return store, nil
+}
+
+// NewPollingConfigStore loads configs from the API like NewAPIConfigStore,
+// but gets updates by polling every interval (or options.PollInterval, if
+// interval is zero) rather than over SSE. Polls are conditional, so they
+// cost little when nothing has changed.
+func NewPollingConfigStore(options options.Options, interval time.Duration, finishedLoading func(), notifier *internal.ConfigChangeNotifier, tracker *connection.Tracker) (*APIConfigStore, error) {
+	store := newAPIConfigStore(options, finishedLoading, notifier, tracker)
+
+	interval = pollInterval(options, interval)
+
+	store.start(func() {
+		store.poll(interval)
+	})
+
+	return store, nil
+}
+
+func newAPIConfigStore(options options.Options, finishedLoading func(), notifier *internal.ConfigChangeNotifier, tracker *connection.Tracker) *APIConfigStore {
+	httpClient, err := internal.BuildHTTPClient(options)
 	if err != nil {
 		panic(err)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if tracker == nil {
+		tracker = connection.NewTracker()
+	}
+
 	store := &APIConfigStore{
-		configMap:       make(map[string]*prefabProto.Config),
-		Initialized:     false,
-		highWatermark:   0,
-		projectEnvID:    0,
-		httpClient:      httpClient,
-		finishedLoading: finishedLoading,
+		configMap:         make(map[string]*prefabProto.Config),
+		contextSet:        contexts.NewContextSet(),
+		Initialized:       false,
+		highWatermark:     0,
+		projectEnvID:      0,
+		httpClient:        httpClient,
+		finishedLoading:   finishedLoading,
+		notifier:          notifier,
+		connection:        tracker,
+		schemaValidator:   internal.NewSchemaValidator(),
+		lastKnownGoodPath: options.LastKnownGoodPath,
+		apiKeyID:          apiKeyID(options),
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+
+	if store.loadLastKnownGood(options.ProjectEnvID) {
+		finishedLoading()
+	}
+
+	if options.MaxStaleness > 0 {
+		store.workers.Add(1)
+
+		go func() {
+			defer store.workers.Done()
+
+			store.watchStaleness(options.MaxStaleness, options.RefetchWhenStale)
+		}()
 	}
 
+	return store
+}
+
+// start fetches the configs in the background and then runs getUpdates until
+// the store is closed.
+func (cs *APIConfigStore) start(getUpdates func()) {
+	cs.workers.Add(1)
+
 	go func() {
-		err = store.fetchFromServer(0, func() {
-			go sse.StartSSEConnection(sseClient, store)
+		defer cs.workers.Done()
+
+		err := cs.fetchFromServer(0, func() {
+			cs.workers.Add(1)
+
+			go func() {
+				defer cs.workers.Done()
+
+				getUpdates()
+			}()
 		})
-		if err != nil {
+		if err != nil && !errors.Is(err, context.Canceled) {
 			slog.Error(fmt.Sprintf("error fetching from server: %v", err))
 		}
 	}()
+}
 
-	return store, nil
+// poll loads any configs newer than the high watermark every interval until
+// the store is closed.
+func (cs *APIConfigStore) poll(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-cs.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// the stream is back and delivering updates itself
+		if cs.connection.Status().State == connection.Streaming {
+			continue
+		}
+
+		configs, err := cs.httpClient.Load(cs.ctx, cs.GetHighWatermark())
+
+		switch {
+		case cs.ctx.Err() != nil:
+			return
+		case errors.Is(err, internal.ErrNotModified):
+			slog.Debug("polled configs are not modified")
+			cs.connection.Synced(cs.GetHighWatermark())
+		case err != nil:
+			slog.Warn(fmt.Sprintf("unable to poll for configs via http %v", err))
+		default:
+			cs.SetFromConfigsProto(configs)
+			cs.connection.Synced(cs.GetHighWatermark())
+			cs.connection.SetState(connection.Loaded, nil)
+
+			// in case the initial fetch gave up before the API came back
+			cs.finishedLoading()
+		}
+	}
+}
+
+// retryStream tries stream again every retryInterval until the store is
+// closed. A stream that connects runs until it gives up again.
+func (cs *APIConfigStore) retryStream(stream *sse.Stream, retryInterval time.Duration) {
+	for {
+		select {
+		case <-cs.ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+
+		slog.Info("trying the config stream again")
+
+		if err := stream.Start(cs.ctx, cs); !errors.Is(err, sse.ErrStreamUnavailable) {
+			return
+		}
+
+		slog.Warn("config stream is still failing, polling for updates until it recovers", "streamRetryInterval", retryInterval)
+	}
+}
+
+// watchStaleness marks the connection stale whenever the API hasn't confirmed
+// the configs (by a load, an update, a keep-alive or a poll) for
+// maxStaleness. With refetch, it then loads every config again, at most once
+// per maxStaleness, in case the stream has silently stopped delivering.
+func (cs *APIConfigStore) watchStaleness(maxStaleness time.Duration, refetch bool) {
+	started := time.Now()
+
+	var lastRefetch time.Time
+
+	ticker := time.NewTicker(max(maxStaleness/4, time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-cs.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		status := cs.connection.Status()
+
+		lastSync := status.LastSync
+		if lastSync.IsZero() {
+			lastSync = started
+		}
+
+		if time.Since(lastSync) < maxStaleness {
+			continue
+		}
+
+		if !status.Stale {
+			slog.Warn("configs are stale", "lastSync", status.LastSync, "maxStaleness", maxStaleness)
+			cs.connection.SetStale(true)
+		}
+
+		if refetch && time.Since(lastRefetch) >= maxStaleness {
+			lastRefetch = time.Now()
+
+			cs.refetch()
+		}
+	}
+}
+
+// refetch loads every config from the API again.
+func (cs *APIConfigStore) refetch() {
+	configs, err := cs.httpClient.Load(cs.ctx, 0)
+
+	switch {
+	case cs.ctx.Err() != nil:
+		return
+	case errors.Is(err, internal.ErrNotModified):
+		cs.connection.Synced(cs.GetHighWatermark())
+	case err != nil:
+		slog.Warn(fmt.Sprintf("unable to refetch stale configs via http %v", err))
+	default:
+		cs.SetFromConfigsProto(configs)
+		cs.connection.Synced(cs.GetHighWatermark())
+
+		if cs.connection.Status().State == connection.Failed {
+			cs.connection.SetState(connection.Loaded, nil)
+		}
+
+		cs.finishedLoading()
+	}
+}
+
+func pollInterval(options options.Options, interval time.Duration) time.Duration {
+	switch {
+	case interval > 0:
+		return interval
+	case options.PollInterval > 0:
+		return options.PollInterval
+	default:
+		return defaultPollInterval
+	}
+}
+
+func streamRetryInterval(options options.Options) time.Duration {
+	if options.StreamRetryInterval > 0 {
+		return options.StreamRetryInterval
+	}
+
+	return defaultStreamRetryInterval
+}
+
+// Close stops the initial fetch (including any pending retries) and the SSE
+// stream, and waits for their goroutines to exit.
+func (cs *APIConfigStore) Close() error {
+	cs.cancel()
+	cs.workers.Wait()
+
+	return nil
 }
 
 func (cs *APIConfigStore) SetConfigs(configs []*prefabProto.Config, envID int64) {
 	cs.Lock()
-	defer cs.Unlock()
 	cs.Initialized = true
 	cs.projectEnvID = envID
 
+	var events []internal.ChangeEvent
+
+	// apply schemas first so the configs that use them are validated against
+	// the schema from the same update
+	sorted := make([]*prefabProto.Config, 0, len(configs))
+	for _, config := range configs {
+		if config.GetConfigType() == prefabProto.ConfigType_SCHEMA {
+			sorted = append(sorted, config)
+		}
+	}
+
 	for _, config := range configs {
-		cs.setConfig(config)
+		if config.GetConfigType() != prefabProto.ConfigType_SCHEMA {
+			sorted = append(sorted, config)
+		}
+	}
+
+	for _, config := range sorted {
+		if event, changed := cs.setConfig(config); changed {
+			events = append(events, event)
+		}
 	}
+
+	cs.Unlock()
+
+	// notify outside the lock so listeners can read from the store
+	cs.notifier.Notify(events)
 }
 
 func (cs *APIConfigStore) SetFromConfigsProto(configs *prefabProto.Configs) {
+	cs.Lock()
 	cs.contextSet = contexts.NewContextSetFromProto(configs.GetDefaultContext())
+	cs.defaultContext = configs.GetDefaultContext()
+	cs.Unlock()
+
 	cs.SetConfigs(configs.GetConfigs(), configs.GetConfigServicePointer().GetProjectEnvId())
+	cs.saveLastKnownGood()
+}
+
+// loadLastKnownGood starts the store from the configs saved by a previous
+// run, if there are any, so it can serve them before (or without) the API
+// responding. Configs saved for another API key, or for an environment other
+// than projectEnvID (when it's given), are ignored.
+func (cs *APIConfigStore) loadLastKnownGood(projectEnvID int64) bool {
+	if cs.lastKnownGoodPath == "" {
+		return false
+	}
+
+	configDump, meta, err := readLastKnownGood(cs.lastKnownGoodPath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("unable to load last known good configs", "err", err)
+		}
+
+		return false
+	}
+
+	savedEnvID := meta.GetConfigServicePointer().GetProjectEnvId()
+
+	switch {
+	case meta.GetConfigServicePointer().GetStartAtId() != configDump.GetMaxConfigId():
+		slog.Warn("ignoring last known good configs whose metadata is for another save", "path", cs.lastKnownGoodPath)
+
+		return false
+	case meta.GetApikeyMetadata().GetKeyId() != cs.apiKeyID:
+		slog.Warn("ignoring last known good configs saved for another API key", "path", cs.lastKnownGoodPath)
+
+		return false
+	case projectEnvID != 0 && savedEnvID != projectEnvID:
+		slog.Warn("ignoring last known good configs saved for another environment", "path", cs.lastKnownGoodPath, "projectEnvID", savedEnvID)
+
+		return false
+	}
+
+	configs := make([]*prefabProto.Config, 0, len(configDump.GetWrappers()))
+
+	for _, wrapper := range configDump.GetWrappers() {
+		if !wrapper.GetDeleted() {
+			configs = append(configs, wrapper.GetConfig())
+		}
+	}
+
+	cs.Lock()
+	cs.contextSet = contexts.NewContextSetFromProto(meta.GetDefaultContext())
+	cs.defaultContext = meta.GetDefaultContext()
+	cs.Unlock()
+
+	cs.SetConfigs(configs, savedEnvID)
+
+	cs.Lock()
+	cs.highWatermark = max(cs.highWatermark, configDump.GetMaxConfigId())
+	cs.Unlock()
+
+	slog.Info("serving last known good configs until the API responds", "path", cs.lastKnownGoodPath, "configs", len(configs))
+
+	return true
+}
+
+// saveLastKnownGood writes the store's configs to lastKnownGoodPath. Saves
+// are serialized and each reads the store afresh, so the file always ends up
+// with the latest configs.
+func (cs *APIConfigStore) saveLastKnownGood() {
+	if cs.lastKnownGoodPath == "" {
+		return
+	}
+
+	cs.lastKnownGoodMutex.Lock()
+	defer cs.lastKnownGoodMutex.Unlock()
+
+	cs.RLock()
+
+	configs := make([]*prefabProto.Config, 0, len(cs.configMap))
+	for _, config := range cs.configMap {
+		configs = append(configs, config)
+	}
+
+	meta := &prefabProto.Configs{
+		ConfigServicePointer: &prefabProto.ConfigServicePointer{
+			ProjectEnvId: cs.projectEnvID,
+			StartAtId:    cs.highWatermark,
+		},
+		ApikeyMetadata: &prefabProto.ApiKeyMetadata{KeyId: &cs.apiKeyID},
+		DefaultContext: cs.defaultContext,
+	}
+
+	cs.RUnlock()
+
+	sort.Slice(configs, func(i, j int) bool { return configs[i].GetKey() < configs[j].GetKey() })
+
+	if err := writeLastKnownGood(cs.lastKnownGoodPath, configs, meta); err != nil {
+		slog.Warn("unable to save last known good configs", "path", cs.lastKnownGoodPath, "err", err)
+	}
+}
+
+// apiKeyID is a hash of the API key (set in options or the environment), safe
+// to save alongside the configs.
+func apiKeyID(options options.Options) string {
+	apiKey, _ := options.APIKeySettingOrEnvVar()
+	sum := sha256.Sum256([]byte(apiKey))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// lastKnownGoodMetaPath is where the metadata for the last known good dump
+// at path is saved: which API key and environment it came from, and the
+// default context. The dump itself stays a plain ConfigDump, so it can also
+// be used as a dump:// source. The metadata records the dump's MaxConfigId
+// so a dump and metadata from different saves aren't used together.
+func lastKnownGoodMetaPath(path string) string {
+	return path + ".meta"
+}
+
+func readLastKnownGood(path string) (*prefabInternalProto.ConfigDump, *prefabProto.Configs, error) {
+	configDump, err := readConfigDump(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metaPath := lastKnownGoodMetaPath(path)
+
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading last known good metadata %s: %w", metaPath, err)
+	}
+
+	var meta prefabProto.Configs
+
+	if err := proto.Unmarshal(data, &meta); err != nil {
+		return nil, nil, fmt.Errorf("error unmarshalling last known good metadata %s: %w", metaPath, err)
+	}
+
+	return configDump, &meta, nil
+}
+
+// writeLastKnownGood saves configs as a ConfigDump at path and meta beside
+// it.
+func writeLastKnownGood(path string, configs []*prefabProto.Config, meta *prefabProto.Configs) error {
+	if err := writeConfigDump(path, configs, meta.GetConfigServicePointer().GetStartAtId()); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	return replaceFile(lastKnownGoodMetaPath(path), data)
 }
 
 func (cs *APIConfigStore) GetContextValue(propertyName string) (interface{}, bool) {
+	cs.RLock()
+	defer cs.RUnlock()
+
 	value, valueExists := cs.contextSet.GetContextValue(propertyName)
 
 	return value, valueExists
 }
 
 func (cs *APIConfigStore) Len() int {
+	cs.RLock()
+	defer cs.RUnlock()
+
 	return len(cs.configMap)
 }
 
@@ -96,20 +565,79 @@ func (cs *APIConfigStore) Keys() []string {
 	return keys
 }
 
-func (cs *APIConfigStore) setConfig(newConfig *prefabProto.Config) {
+func (cs *APIConfigStore) setConfig(newConfig *prefabProto.Config) (internal.ChangeEvent, bool) {
 	newConfigIsEmpty := len(newConfig.GetRows()) == 0
 	currentConfig, exists := cs.configMap[newConfig.GetKey()]
 
+	isNewer := !exists || newConfig.GetId() > currentConfig.GetId()
+
+	event := internal.ChangeEvent{Key: newConfig.GetKey(), ConfigID: newConfig.GetId()}
+	changed := false
+
 	switch {
-	case newConfigIsEmpty && exists && newConfig.GetId() > currentConfig.GetId():
+	case newConfigIsEmpty && exists && isNewer:
 		delete(cs.configMap, newConfig.GetKey())
-	case !newConfigIsEmpty && (exists && newConfig.GetId() > currentConfig.GetId()) || (!exists):
+
+		event.Type = internal.ChangeTypeDeleted
+		event.Old = currentConfig
+		changed = true
+	case !newConfigIsEmpty && isNewer && !cs.matchesSchema(newConfig):
+		// keep the last valid version, if there is one
+	case !newConfigIsEmpty && isNewer && !hasAllowedValues(newConfig):
+		// keep the last valid version, if there is one
+	case !newConfigIsEmpty && isNewer || !exists:
 		cs.configMap[newConfig.GetKey()] = newConfig
+
+		if exists {
+			event.Type = internal.ChangeTypeUpdated
+			event.Old = currentConfig
+		} else {
+			event.Type = internal.ChangeTypeAdded
+		}
+
+		event.New = newConfig
+		// a tombstone for a key we've never seen isn't worth announcing
+		changed = !newConfigIsEmpty
 	}
 
 	if newConfig.GetId() > cs.highWatermark {
 		cs.highWatermark = newConfig.GetId()
 	}
+
+	return event, changed
+}
+
+// matchesSchema reports whether config's JSON values match its schema. It
+// must be called with the lock held.
+func (cs *APIConfigStore) matchesSchema(config *prefabProto.Config) bool {
+	if config.GetSchemaKey() == "" {
+		return true
+	}
+
+	schemaConfig, exists := cs.configMap[config.GetSchemaKey()]
+	if !exists {
+		return true
+	}
+
+	if err := cs.schemaValidator.ValidateConfig(config, internal.SchemaFromConfig(schemaConfig, cs.projectEnvID)); err != nil {
+		slog.Warn("ignoring config update that does not match its schema", "key", config.GetKey(), "id", config.GetId(), "err", err)
+
+		return false
+	}
+
+	return true
+}
+
+// hasAllowedValues reports whether every literal value in config is one of
+// its AllowableValues.
+func hasAllowedValues(config *prefabProto.Config) bool {
+	if err := internal.CheckAllowableValues(config); err != nil {
+		slog.Warn("ignoring config update with a value that is not allowed", "key", config.GetKey(), "id", config.GetId(), "err", err)
+
+		return false
+	}
+
+	return true
 }
 
 // GetConfig retrieves a Config associated with the given key.
@@ -132,16 +660,33 @@ func (cs *APIConfigStore) GetProjectEnvID() int64 {
 }
 
 func (cs *APIConfigStore) fetchFromServer(retriesAttempted int, then func()) error {
-	configs, err := cs.httpClient.Load(cs.highWatermark)
+	configs, err := cs.httpClient.Load(cs.ctx, cs.GetHighWatermark())
 	if err != nil {
+		if cs.ctx.Err() != nil {
+			return cs.ctx.Err()
+		}
+
+		if errors.Is(err, internal.ErrNotModified) {
+			cs.connection.Synced(cs.GetHighWatermark())
+			cs.connection.SetState(connection.Loaded, nil)
+			cs.finishedLoading()
+			then()
+
+			return nil
+		}
+
 		slog.Warn(fmt.Sprintf("unable to get data via http %v", err))
 
 		if retriesAttempted < maxRetries {
-			retryDelay := time.Duration(retriesAttempted) * time.Second
+			retryDelay := time.Duration(retriesAttempted) * fetchRetryDelay
 
-			slog.Debug(fmt.Sprintf("retrying in %d seconds, attempt %d/%d", int(retryDelay.Seconds()), retriesAttempted+1, maxRetries))
+			slog.Debug(fmt.Sprintf("retrying in %s, attempt %d/%d", retryDelay, retriesAttempted+1, maxRetries))
 
-			time.Sleep(retryDelay)
+			select {
+			case <-cs.ctx.Done():
+				return cs.ctx.Err()
+			case <-time.After(retryDelay):
+			}
 
 			err = cs.fetchFromServer(retriesAttempted+1, then)
 			if err != nil {
@@ -149,6 +694,7 @@ func (cs *APIConfigStore) fetchFromServer(retriesAttempted int, then func()) err
 			}
 		} else {
 			slog.Error("max retries reached, giving up")
+			cs.connection.SetState(connection.Failed, err)
 			then()
 
 			return err
@@ -159,6 +705,8 @@ func (cs *APIConfigStore) fetchFromServer(retriesAttempted int, then func()) err
 
 	slog.Debug("Loaded configuration data")
 	cs.SetFromConfigsProto(configs)
+	cs.connection.Synced(cs.GetHighWatermark())
+	cs.connection.SetState(connection.Loaded, nil)
 
 	cs.finishedLoading()
 
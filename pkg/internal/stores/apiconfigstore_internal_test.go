@@ -0,0 +1,74 @@
+package stores
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/connection"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
+)
+
+func TestApiConfigStoreStaysFailedWhilePollingADownAPI(t *testing.T) {
+	defaultRetryDelay := fetchRetryDelay
+	fetchRetryDelay = time.Millisecond
+
+	t.Cleanup(func() { fetchRetryDelay = defaultRetryDelay })
+
+	var loads atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/v1/configs/") {
+			loads.Add(1)
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	opts := options.Options{
+		APIKey:                   "does-not-matter",
+		APIURLs:                  []string{server.URL},
+		PollInterval:             20 * time.Millisecond,
+		SSEFailuresBeforePolling: 1,
+		StreamRetryInterval:      time.Hour,
+	}
+
+	tracker := connection.NewTracker()
+
+	var (
+		mutex  sync.Mutex
+		states []connection.State
+	)
+
+	tracker.Subscribe(func(change connection.StateChange) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		states = append(states, change.Status.State)
+	})
+
+	var finishedLoading atomic.Bool
+
+	store, err := NewAPIConfigStore(opts, func() { finishedLoading.Store(true) }, nil, tracker)
+	require.NoError(t, err)
+
+	defer store.Close()
+
+	// the initial fetch and its retries, then a few polls
+	assert.Eventually(t, func() bool { return loads.Load() > maxRetries+5 }, 10*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, connection.Failed, tracker.Status().State)
+	assert.False(t, finishedLoading.Load())
+
+	mutex.Lock()
+	assert.NotContains(t, states, connection.Loaded)
+	mutex.Unlock()
+}
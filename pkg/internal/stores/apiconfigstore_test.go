@@ -1,12 +1,22 @@
 package stores_test
 
 import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/connection"
 	opts "github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/stores"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
@@ -83,7 +93,7 @@ func TestApiConfigStore(t *testing.T) {
 	emptyConfigs := &prefabProto.Configs{}
 
 	t.Run("store initialized after set called and has two values", func(t *testing.T) {
-		store, _ := stores.NewAPIConfigStore(options, func() {})
+		store, _ := stores.NewAPIConfigStore(options, func() {}, nil, nil)
 		store.SetFromConfigsProto(configs)
 		assert.Equal(t, 2, store.Len())
 		assert.True(t, store.Initialized)
@@ -101,7 +111,7 @@ func TestApiConfigStore(t *testing.T) {
 	})
 
 	t.Run("store initialized with empty configs still marked initialized", func(t *testing.T) {
-		store, _ := stores.NewAPIConfigStore(options, func() {})
+		store, _ := stores.NewAPIConfigStore(options, func() {}, nil, nil)
 		store.SetFromConfigsProto(emptyConfigs)
 		assert.Equal(t, 0, store.Len())
 		assert.True(t, store.Initialized)
@@ -113,7 +123,7 @@ func TestApiConfigStore(t *testing.T) {
 	})
 
 	t.Run("updating with tombstoned config foo deletes", func(t *testing.T) {
-		store, _ := stores.NewAPIConfigStore(options, func() {})
+		store, _ := stores.NewAPIConfigStore(options, func() {}, nil, nil)
 		store.SetFromConfigsProto(configs)
 		assert.Equal(t, 2, store.Len())
 		assert.True(t, store.Initialized)
@@ -133,7 +143,7 @@ func TestApiConfigStore(t *testing.T) {
 	})
 
 	t.Run("updating with tombstoned config foo does nothing with smaller id", func(t *testing.T) {
-		store, _ := stores.NewAPIConfigStore(options, func() {})
+		store, _ := stores.NewAPIConfigStore(options, func() {}, nil, nil)
 		store.SetFromConfigsProto(configs)
 		assert.Equal(t, 2, store.Len())
 		assert.True(t, store.Initialized)
@@ -155,7 +165,7 @@ func TestApiConfigStore(t *testing.T) {
 	})
 
 	t.Run("updating with changed config foo does nothing with smaller id", func(t *testing.T) {
-		store, _ := stores.NewAPIConfigStore(options, func() {})
+		store, _ := stores.NewAPIConfigStore(options, func() {}, nil, nil)
 		store.SetFromConfigsProto(configs)
 		assert.Equal(t, 2, store.Len())
 		assert.True(t, store.Initialized)
@@ -177,7 +187,7 @@ func TestApiConfigStore(t *testing.T) {
 	})
 
 	t.Run("updating with changed config foo updates when id is larger", func(t *testing.T) {
-		store, _ := stores.NewAPIConfigStore(options, func() {})
+		store, _ := stores.NewAPIConfigStore(options, func() {}, nil, nil)
 		store.SetFromConfigsProto(configs)
 		assert.Equal(t, 2, store.Len())
 		assert.True(t, store.Initialized)
@@ -194,4 +204,647 @@ func TestApiConfigStore(t *testing.T) {
 		assert.NotNil(t, foo)
 		assert.Equal(t, configFooWithDifferentValue, foo)
 	})
+	t.Run("changes are reported to the notifier", func(t *testing.T) {
+		notifier := internal.NewConfigChangeNotifier()
+
+		var events []internal.ChangeEvent
+
+		notifier.Subscribe(func(event internal.ChangeEvent) { events = append(events, event) })
+
+		store, _ := stores.NewAPIConfigStore(options, func() {}, notifier, nil)
+		store.SetFromConfigsProto(configs)
+		store.SetFromConfigsProto(&prefabProto.Configs{Configs: []*prefabProto.Config{configFooWithDifferentValue}})
+
+		barTombstone := &prefabProto.Config{Key: "bar", Id: 12}
+		unknownTombstone := &prefabProto.Config{Key: "never-seen", Id: 13}
+		store.SetFromConfigsProto(&prefabProto.Configs{Configs: []*prefabProto.Config{barTombstone, unknownTombstone}})
+
+		// an older version of a config is ignored and not reported
+		store.SetFromConfigsProto(&prefabProto.Configs{Configs: []*prefabProto.Config{configFoo}})
+
+		assert.Equal(t, []internal.ChangeEvent{
+			{Key: "foo", ConfigID: 10, New: configFoo, Type: internal.ChangeTypeAdded},
+			{Key: "bar", ConfigID: 10, New: configBar, Type: internal.ChangeTypeAdded},
+			{Key: "foo", ConfigID: 11, Old: configFoo, New: configFooWithDifferentValue, Type: internal.ChangeTypeUpdated},
+			{Key: "bar", ConfigID: 12, Old: configBar, Type: internal.ChangeTypeDeleted},
+		}, events)
+	})
+
+	t.Run("updates that do not match their schema are ignored", func(t *testing.T) {
+		schemaConfig := func(id int64, schema string) *prefabProto.Config {
+			return &prefabProto.Config{
+				Key:        "settings-schema",
+				Id:         id,
+				ConfigType: prefabProto.ConfigType_SCHEMA,
+				Rows: []*prefabProto.ConfigRow{{Values: []*prefabProto.ConditionalValue{{
+					Value: &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Schema{Schema: &prefabProto.Schema{
+						Schema:     schema,
+						SchemaType: prefabProto.Schema_JSON_SCHEMA,
+					}}},
+				}}}},
+			}
+		}
+
+		settings := func(id int64, json string) *prefabProto.Config {
+			return &prefabProto.Config{
+				Key:        "settings",
+				Id:         id,
+				ConfigType: prefabProto.ConfigType_CONFIG,
+				SchemaKey:  internal.StringPtr("settings-schema"),
+				Rows: []*prefabProto.ConfigRow{{Values: []*prefabProto.ConditionalValue{{
+					Value: &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Json{Json: &prefabProto.Json{Json: json}}},
+				}}}},
+			}
+		}
+
+		requiresName := schemaConfig(20, `{"type": "object", "required": ["name"]}`)
+		valid := settings(21, `{"name": "a"}`)
+
+		store, _ := stores.NewAPIConfigStore(options, func() {}, nil, nil)
+		store.SetFromConfigsProto(&prefabProto.Configs{Configs: []*prefabProto.Config{valid, requiresName}})
+
+		store.SetFromConfigsProto(&prefabProto.Configs{Configs: []*prefabProto.Config{settings(22, `{"other": "a"}`)}})
+
+		current, _ := store.GetConfig("settings")
+		assert.Equal(t, valid, current)
+
+		// the schema is applied before the config it arrives with
+		relaxed := settings(23, `{"other": "a"}`)
+		store.SetFromConfigsProto(&prefabProto.Configs{Configs: []*prefabProto.Config{relaxed, schemaConfig(24, `{"type": "object"}`)}})
+
+		current, _ = store.GetConfig("settings")
+		assert.Equal(t, relaxed, current)
+	})
+}
+
+func TestApiConfigStoreCloseStopsRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	options := opts.Options{APIKey: "does-not-matter", APIURLs: []string{server.URL}}
+
+	finishedLoading := false
+	store, err := stores.NewAPIConfigStore(options, func() { finishedLoading = true }, nil, nil)
+	require.NoError(t, err)
+
+	closed := make(chan struct{})
+
+	go func() {
+		_ = store.Close()
+
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return while the store was retrying")
+	}
+
+	assert.False(t, finishedLoading)
+}
+
+func TestApiConfigStoreLastKnownGood(t *testing.T) {
+	requestedPaths := make(chan string, 100)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths <- r.URL.Path
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	options := opts.Options{
+		APIKey:            "does-not-matter",
+		APIURLs:           []string{server.URL},
+		LastKnownGoodPath: filepath.Join(t.TempDir(), "last-known-good.dump"),
+	}
+
+	configFoo := &prefabProto.Config{
+		Key:        "foo",
+		Id:         12,
+		ConfigType: prefabProto.ConfigType_CONFIG,
+		Rows: []*prefabProto.ConfigRow{{
+			ProjectEnvId: internal.Int64Ptr(101),
+			Values:       []*prefabProto.ConditionalValue{{Value: testutils.CreateConfigValueAndAssertOk(t, "foo-value")}},
+		}},
+	}
+
+	firstRun, err := stores.NewAPIConfigStore(options, func() {}, nil, nil)
+	require.NoError(t, err)
+
+	firstRun.SetFromConfigsProto(&prefabProto.Configs{
+		Configs:              []*prefabProto.Config{configFoo},
+		ConfigServicePointer: &prefabProto.ConfigServicePointer{ProjectEnvId: 101},
+		DefaultContext: &prefabProto.ContextSet{Contexts: []*prefabProto.Context{{
+			Type:   internal.StringPtr("prefab-api-key"),
+			Values: map[string]*prefabProto.ConfigValue{"user-id": testutils.CreateConfigValueAndAssertOk(t, "42")},
+		}}},
+	})
+	require.NoError(t, firstRun.Close())
+
+	finishedLoading := false
+
+	secondRun, err := stores.NewAPIConfigStore(options, func() { finishedLoading = true }, nil, nil)
+	require.NoError(t, err)
+
+	defer secondRun.Close()
+
+	assert.True(t, finishedLoading, "the saved configs are served right away")
+	assert.True(t, secondRun.Initialized)
+	assert.Equal(t, int64(101), secondRun.GetProjectEnvID())
+	assert.Equal(t, int64(12), secondRun.GetHighWatermark())
+
+	config, exists := secondRun.GetConfig("foo")
+	require.True(t, exists)
+	assert.True(t, proto.Equal(configFoo, config))
+
+	userID, exists := secondRun.GetContextValue("prefab-api-key.user-id")
+	require.True(t, exists, "the default context is restored")
+	assert.Equal(t, "42", userID)
+
+	for {
+		select {
+		case path := <-requestedPaths:
+			if strings.HasPrefix(path, "/api/v1/configs/") {
+				assert.Equal(t, "/api/v1/configs/12", path, "loading resumes after the saved configs")
+
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("the API was never asked for configs")
+		}
+	}
+}
+
+func TestApiConfigStoreIgnoresMismatchedLastKnownGood(t *testing.T) {
+	server := unavailableAPI(t)
+
+	saved := opts.Options{
+		APIKey:            "first-key",
+		APIURLs:           []string{server.URL},
+		LastKnownGoodPath: filepath.Join(t.TempDir(), "last-known-good.dump"),
+	}
+
+	saveLastKnownGood(t, saved)
+
+	otherKey := saved
+	otherKey.APIKey = "second-key"
+
+	otherEnv := saved
+	otherEnv.ProjectEnvID = 102
+
+	sameEnv := saved
+	sameEnv.ProjectEnvID = 101
+
+	tests := []struct {
+		name    string
+		options opts.Options
+		loaded  bool
+	}{
+		{"another API key", otherKey, false},
+		{"another environment", otherEnv, false},
+		{"the same environment", sameEnv, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.loaded, loadsLastKnownGood(t, tt.options))
+		})
+	}
+}
+
+func TestApiConfigStoreLastKnownGoodWithAPIKeyFromEnv(t *testing.T) {
+	server := unavailableAPI(t)
+
+	options := opts.Options{
+		APIURLs:           []string{server.URL},
+		LastKnownGoodPath: filepath.Join(t.TempDir(), "last-known-good.dump"),
+	}
+
+	t.Setenv(opts.APIKeyEnvVar, "first-key")
+	saveLastKnownGood(t, options)
+
+	t.Setenv(opts.APIKeyEnvVar, "second-key")
+	assert.False(t, loadsLastKnownGood(t, options), "configs saved under another API key are ignored")
+
+	t.Setenv(opts.APIKeyEnvVar, "first-key")
+	assert.True(t, loadsLastKnownGood(t, options))
+}
+
+func TestLastKnownGoodIsAConfigDump(t *testing.T) {
+	server := unavailableAPI(t)
+
+	options := opts.Options{
+		APIKey:            "does-not-matter",
+		APIURLs:           []string{server.URL},
+		LastKnownGoodPath: filepath.Join(t.TempDir(), "last-known-good.dump"),
+	}
+
+	saveLastKnownGood(t, options)
+
+	store, err := stores.NewConfigDumpConfigStore(options.LastKnownGoodPath, 101)
+	require.NoError(t, err)
+
+	config, exists := store.GetConfig("foo")
+	require.True(t, exists)
+	assert.Equal(t, int64(12), config.GetId())
+}
+
+// unavailableAPI answers every request with a 503.
+func unavailableAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// saveLastKnownGood saves a "foo" config for environment 101 to
+// options.LastKnownGoodPath.
+func saveLastKnownGood(t *testing.T, options opts.Options) {
+	t.Helper()
+
+	store, err := stores.NewAPIConfigStore(options, func() {}, nil, nil)
+	require.NoError(t, err)
+
+	store.SetFromConfigsProto(&prefabProto.Configs{
+		Configs: []*prefabProto.Config{{
+			Key:        "foo",
+			Id:         12,
+			ConfigType: prefabProto.ConfigType_CONFIG,
+			Rows: []*prefabProto.ConfigRow{{
+				ProjectEnvId: internal.Int64Ptr(101),
+				Values:       []*prefabProto.ConditionalValue{{Value: testutils.CreateConfigValueAndAssertOk(t, "foo-value")}},
+			}},
+		}},
+		ConfigServicePointer: &prefabProto.ConfigServicePointer{ProjectEnvId: 101},
+	})
+	require.NoError(t, store.Close())
+}
+
+// loadsLastKnownGood reports whether a store with options starts from the
+// saved configs.
+func loadsLastKnownGood(t *testing.T, options opts.Options) bool {
+	t.Helper()
+
+	finishedLoading := false
+
+	store, err := stores.NewAPIConfigStore(options, func() { finishedLoading = true }, nil, nil)
+	require.NoError(t, err)
+
+	defer store.Close()
+
+	_, exists := store.GetConfig("foo")
+	assert.Equal(t, exists, finishedLoading)
+
+	return exists
+}
+
+// conditionalAPI serves its current configs from the configs endpoint with an
+// ETag, answering 304 when the client already has them, and refuses SSE.
+type conditionalAPI struct {
+	configs     *prefabProto.Configs
+	etag        string
+	notModified int
+	loads       int
+	// streaming makes the config stream send the configs and stay open,
+	// rather than refuse the connection.
+	streaming bool
+	sync.Mutex
+}
+
+func (api *conditionalAPI) setStreaming(streaming bool) {
+	api.Lock()
+	defer api.Unlock()
+
+	api.streaming = streaming
+}
+
+func (api *conditionalAPI) loadCount() int {
+	api.Lock()
+	defer api.Unlock()
+
+	return api.loads
+}
+
+func (api *conditionalAPI) set(configs *prefabProto.Configs, etag string) {
+	api.Lock()
+	defer api.Unlock()
+
+	api.configs = configs
+	api.etag = etag
+}
+
+func (api *conditionalAPI) notModifiedCount() int {
+	api.Lock()
+	defer api.Unlock()
+
+	return api.notModified
+}
+
+func (api *conditionalAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v1/sse/config" {
+		api.serveStream(w, r)
+
+		return
+	}
+
+	api.Lock()
+	defer api.Unlock()
+
+	if !strings.HasPrefix(r.URL.Path, "/api/v1/configs/") {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	api.loads++
+
+	if r.Header.Get("If-None-Match") == api.etag {
+		api.notModified++
+
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	body, err := proto.Marshal(api.configs)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("ETag", api.etag)
+	_, _ = w.Write(body)
+}
+
+func (api *conditionalAPI) serveStream(w http.ResponseWriter, r *http.Request) {
+	api.Lock()
+	streaming := api.streaming
+	body, err := proto.Marshal(api.configs)
+	api.Unlock()
+
+	if !streaming || err != nil {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	_, _ = fmt.Fprintf(w, "data: %s\n\n", base64.StdEncoding.EncodeToString(body))
+	w.(http.Flusher).Flush()
+
+	<-r.Context().Done()
+}
+
+func stringConfigs(t *testing.T, id int64, value string) *prefabProto.Configs {
+	t.Helper()
+
+	return &prefabProto.Configs{
+		Configs: []*prefabProto.Config{{
+			Key:        "foo",
+			Id:         id,
+			ConfigType: prefabProto.ConfigType_CONFIG,
+			Rows: []*prefabProto.ConfigRow{{
+				ProjectEnvId: internal.Int64Ptr(101),
+				Values:       []*prefabProto.ConditionalValue{{Value: testutils.CreateConfigValueAndAssertOk(t, value)}},
+			}},
+		}},
+		ConfigServicePointer: &prefabProto.ConfigServicePointer{ProjectEnvId: 101},
+	}
+}
+
+func assertEventuallyHasValue(t *testing.T, store *stores.APIConfigStore, value string) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		config, exists := store.GetConfig("foo")
+
+		return exists && config.GetRows()[0].GetValues()[0].GetValue().GetString_() == value
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestPollingConfigStore(t *testing.T) {
+	api := &conditionalAPI{}
+	api.set(stringConfigs(t, 1, "first"), `"v1"`)
+
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	options := opts.Options{APIKey: "does-not-matter", APIURLs: []string{server.URL}}
+
+	store, err := stores.NewPollingConfigStore(options, 20*time.Millisecond, func() {}, nil, nil)
+	require.NoError(t, err)
+
+	defer store.Close()
+
+	assertEventuallyHasValue(t, store, "first")
+
+	// polling from the same offset sends the ETag it got, so an unchanged
+	// API answers 304
+	assert.Eventually(t, func() bool { return api.notModifiedCount() > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	api.set(stringConfigs(t, 2, "second"), `"v2"`)
+
+	assertEventuallyHasValue(t, store, "second")
+}
+
+func TestApiConfigStoreFallsBackToPolling(t *testing.T) {
+	api := &conditionalAPI{}
+	api.set(stringConfigs(t, 1, "first"), `"v1"`)
+
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	options := opts.Options{
+		APIKey:                   "does-not-matter",
+		APIURLs:                  []string{server.URL},
+		PollInterval:             20 * time.Millisecond,
+		SSEFailuresBeforePolling: 2,
+	}
+
+	store, err := stores.NewAPIConfigStore(options, func() {}, nil, nil)
+	require.NoError(t, err)
+
+	defer store.Close()
+
+	assertEventuallyHasValue(t, store, "first")
+
+	api.set(stringConfigs(t, 2, "second"), `"v2"`)
+
+	assertEventuallyHasValue(t, store, "second")
+}
+
+func TestApiConfigStoreRetriesTheStreamWhilePolling(t *testing.T) {
+	api := &conditionalAPI{}
+	api.set(stringConfigs(t, 1, "first"), `"v1"`)
+
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	options := opts.Options{
+		APIKey:                   "does-not-matter",
+		APIURLs:                  []string{server.URL},
+		PollInterval:             20 * time.Millisecond,
+		SSEFailuresBeforePolling: 2,
+		StreamRetryInterval:      200 * time.Millisecond,
+	}
+
+	tracker := connection.NewTracker()
+
+	store, err := stores.NewAPIConfigStore(options, func() {}, nil, tracker)
+	require.NoError(t, err)
+
+	defer store.Close()
+
+	assertEventuallyHasValue(t, store, "first")
+
+	// polls answered with a 304 show the store has fallen back to polling
+	assert.Eventually(t, func() bool { return api.notModifiedCount() > 0 }, 10*time.Second, 10*time.Millisecond)
+
+	api.set(stringConfigs(t, 2, "second"), `"v2"`)
+	api.setStreaming(true)
+
+	assert.Eventually(t, func() bool { return tracker.Status().State == connection.Streaming }, 10*time.Second, 10*time.Millisecond)
+	assertEventuallyHasValue(t, store, "second")
+
+	loads := api.loadCount()
+
+	time.Sleep(10 * options.PollInterval)
+
+	// a poll that was already under way may still land
+	assert.LessOrEqual(t, api.loadCount(), loads+1, "polling pauses while the stream delivers")
+}
+
+func TestApiConfigStoreRefetchesWhenStale(t *testing.T) {
+	api := &conditionalAPI{}
+	api.set(stringConfigs(t, 1, "first"), `"v1"`)
+
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	// the API refuses the stream, so nothing but refetches keeps the
+	// configs fresh
+	options := opts.Options{
+		APIKey:           "does-not-matter",
+		APIURLs:          []string{server.URL},
+		MaxStaleness:     100 * time.Millisecond,
+		RefetchWhenStale: true,
+	}
+
+	tracker := connection.NewTracker()
+
+	var (
+		mutex      sync.Mutex
+		staleFlips []bool
+	)
+
+	tracker.Subscribe(func(change connection.StateChange) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if change.Status.Stale != change.WasStale {
+			staleFlips = append(staleFlips, change.Status.Stale)
+		}
+	})
+
+	store, err := stores.NewAPIConfigStore(options, func() {}, nil, tracker)
+	require.NoError(t, err)
+
+	defer store.Close()
+
+	assertEventuallyHasValue(t, store, "first")
+
+	// an unchanged API answers the refetch with a 304, which still counts
+	// as a sync
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return api.notModifiedCount() > 0 && len(staleFlips) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mutex.Lock()
+	assert.Equal(t, []bool{true, false}, staleFlips[:2])
+	mutex.Unlock()
+
+	api.set(stringConfigs(t, 2, "second"), `"v2"`)
+
+	assertEventuallyHasValue(t, store, "second")
+}
+
+func TestApiConfigStoreRefetchesAlongsideStreamUpdates(t *testing.T) {
+	api := &conditionalAPI{}
+	api.set(stringConfigs(t, 1, "first"), `"v1"`)
+
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	options := opts.Options{
+		APIKey:           "does-not-matter",
+		APIURLs:          []string{server.URL},
+		MaxStaleness:     time.Millisecond,
+		RefetchWhenStale: true,
+	}
+
+	store, err := stores.NewAPIConfigStore(options, func() {}, nil, nil)
+	require.NoError(t, err)
+
+	defer store.Close()
+
+	assertEventuallyHasValue(t, store, "first")
+
+	// apply updates the way the stream does while refetches run on the
+	// staleness watcher's goroutine; -race catches unguarded state
+	update := stringConfigs(t, 2, "second")
+	update.DefaultContext = &prefabProto.ContextSet{Contexts: []*prefabProto.Context{{
+		Type:   internal.StringPtr("prefab-api-key"),
+		Values: map[string]*prefabProto.ConfigValue{"user-id": testutils.CreateConfigValueAndAssertOk(t, "42")},
+	}}}
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for i := 0; time.Now().Before(deadline); i++ {
+		// a new ETag each time so refetches get configs, not a 304
+		api.set(stringConfigs(t, 1, "first"), fmt.Sprintf(`"v%d"`, i))
+		store.SetFromConfigsProto(update)
+		store.GetContextValue("prefab-api-key.user-id")
+		store.Len()
+	}
+
+	assert.Greater(t, api.loadCount(), 1, "refetches ran alongside the updates")
+}
+
+func TestApiConfigStoreStaysStaleWithoutRefetch(t *testing.T) {
+	api := &conditionalAPI{}
+	api.set(stringConfigs(t, 1, "first"), `"v1"`)
+
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	options := opts.Options{
+		APIKey:       "does-not-matter",
+		APIURLs:      []string{server.URL},
+		MaxStaleness: 50 * time.Millisecond,
+	}
+
+	tracker := connection.NewTracker()
+
+	store, err := stores.NewAPIConfigStore(options, func() {}, nil, tracker)
+	require.NoError(t, err)
+
+	defer store.Close()
+
+	assert.Eventually(t, func() bool { return tracker.Status().Stale }, 5*time.Second, 10*time.Millisecond)
+
+	time.Sleep(200 * time.Millisecond)
+
+	assert.True(t, tracker.Status().Stale)
+	assert.Zero(t, api.notModifiedCount(), "nothing was refetched")
 }
@@ -1,6 +1,9 @@
 package stores
 
 import (
+	"errors"
+	"io"
+
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
 	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
@@ -31,6 +34,20 @@ func (s *CompositeConfigStore) GetConfig(key string) (*prefabProto.Config, bool)
 	return nil, false
 }
 
+// GetAllowableValues returns the AllowableValues of the first store's config
+// for key that has any, so local overrides are held to the values allowed
+// upstream.
+func (s *CompositeConfigStore) GetAllowableValues(key string) []*prefabProto.ConfigValue {
+	for _, store := range s.stores {
+		config, exists := store.GetConfig(key)
+		if exists && len(config.GetAllowableValues()) > 0 {
+			return config.GetAllowableValues()
+		}
+	}
+
+	return nil
+}
+
 func (s *CompositeConfigStore) GetContextValue(propertyName string) (interface{}, bool) {
 	for _, store := range s.stores {
 		value, valueExists := store.GetContextValue(propertyName)
@@ -71,3 +88,19 @@ func (s *CompositeConfigStore) GetProjectEnvID() int64 {
 
 	return 0
 }
+
+// Close closes every underlying store that holds resources (e.g. the API
+// store's fetch and SSE goroutines).
+func (s *CompositeConfigStore) Close() error {
+	var errs []error
+
+	for _, store := range s.stores {
+		if closer, ok := store.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	prefabInternalProto "github.com/prefab-cloud/prefab-cloud-go/internal-proto"
 	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
@@ -28,12 +30,32 @@ func NewConfigDumpConfigStore(path string, projectEnvID int64) (*ConfigDumpConfi
 		return nil, errors.New("projectEnvID must be provided for ConfigDumpConfigStore")
 	}
 
+	if err := validateConfigs(configMap, projectEnvID); err != nil {
+		return nil, fmt.Errorf("error creating ConfigDumpConfigStore: %w", err)
+	}
+
 	return &ConfigDumpConfigStore{configMap: configMap, Initialized: true, path: path, ProjectEnvID: projectEnvID}, nil
 }
 
 func configDumpFileToConfigMap(path string) (map[string]*prefabProto.Config, error) {
 	configMap := make(map[string]*prefabProto.Config)
 
+	configDump, err := readConfigDump(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, wrapper := range configDump.GetWrappers() {
+		if !wrapper.GetDeleted() {
+			config := wrapper.GetConfig()
+			configMap[config.GetKey()] = config
+		}
+	}
+
+	return configMap, nil
+}
+
+func readConfigDump(path string) (*prefabInternalProto.ConfigDump, error) {
 	var configDump prefabInternalProto.ConfigDump
 
 	data, err := os.ReadFile(path)
@@ -46,14 +68,50 @@ func configDumpFileToConfigMap(path string) (map[string]*prefabProto.Config, err
 		return nil, fmt.Errorf("error unmarshalling config dump file %s: %w", path, err)
 	}
 
-	for _, wrapper := range configDump.GetWrappers() {
-		if !wrapper.GetDeleted() {
-			config := wrapper.GetConfig()
-			configMap[config.GetKey()] = config
-		}
+	return &configDump, nil
+}
+
+// writeConfigDump replaces the file at path with a ConfigDump of configs.
+func writeConfigDump(path string, configs []*prefabProto.Config, maxConfigID int64) error {
+	configDump := &prefabInternalProto.ConfigDump{
+		CreatedAt:   timestamppb.Now(),
+		MaxConfigId: maxConfigID,
+		Wrappers:    make([]*prefabInternalProto.ConfigWrapper, 0, len(configs)),
 	}
 
-	return configMap, nil
+	for _, config := range configs {
+		configDump.Wrappers = append(configDump.Wrappers, &prefabInternalProto.ConfigWrapper{Config: config})
+	}
+
+	data, err := proto.Marshal(configDump)
+	if err != nil {
+		return err
+	}
+
+	return replaceFile(path, data)
+}
+
+// replaceFile replaces the file at path with data. It is written to a
+// temporary file first so readers never see a partial file.
+func replaceFile(path string, data []byte) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+
+		return err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), path)
 }
 
 func (s *ConfigDumpConfigStore) GetConfig(key string) (*prefabProto.Config, bool) {
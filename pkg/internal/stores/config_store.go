@@ -4,13 +4,19 @@ import (
 	"fmt"
 
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/connection"
 	opts "github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
 
-func BuildConfigStore(options opts.Options, source opts.ConfigSource, apiSourceFinishedLoading func()) (internal.ConfigStoreGetter, bool, error) {
+func BuildConfigStore(options opts.Options, source opts.ConfigSource, apiSourceFinishedLoading func(), notifier *internal.ConfigChangeNotifier, tracker *connection.Tracker) (internal.ConfigStoreGetter, bool, error) {
 	switch source.Store {
 	case opts.APIStore:
-		store, err := NewAPIConfigStore(options, apiSourceFinishedLoading)
+		store, err := NewAPIConfigStore(options, apiSourceFinishedLoading, notifier, tracker)
+
+		return store, true, err
+	case opts.Poll:
+		store, err := NewPollingConfigStore(options, source.Interval, apiSourceFinishedLoading, notifier, tracker)
 
 		return store, true, err
 	case opts.DataFile:
@@ -29,3 +35,29 @@ func BuildConfigStore(options opts.Options, source opts.ConfigSource, apiSourceF
 		return nil, false, fmt.Errorf("unknown store type %v", source.Store)
 	}
 }
+
+// validateConfigs checks the configs of a store that's loaded once (a
+// datafile, dump or WithConfigs) like the API store checks configs as they
+// arrive: literal values must be allowed and JSON values must match their
+// schema. Schemas that aren't in configMap (e.g. ones that come from the API)
+// are only checked when the config is evaluated.
+func validateConfigs(configMap map[string]*prefabProto.Config, projectEnvID int64) error {
+	validator := internal.NewSchemaValidator()
+
+	for _, config := range configMap {
+		if err := internal.CheckAllowableValues(config); err != nil {
+			return err
+		}
+
+		schemaConfig, exists := configMap[config.GetSchemaKey()]
+		if config.GetSchemaKey() == "" || !exists {
+			continue
+		}
+
+		if err := validator.ValidateConfig(config, internal.SchemaFromConfig(schemaConfig, projectEnvID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -25,6 +25,10 @@ func NewLocalConfigStore(path string) (*LocalConfigStore, error) {
 		return nil, err
 	}
 
+	if err := validateConfigs(configMap, projectEnvID); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
 	return &LocalConfigStore{configMap: configMap, projectEnvID: projectEnvID, Initialized: true}, nil
 }
 
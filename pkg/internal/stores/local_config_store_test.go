@@ -1,10 +1,13 @@
 package stores_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/stores"
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
@@ -112,3 +115,25 @@ func (suite *LocalConfigStoreSuite) onlyValue(config *prefabProto.Config) (*pref
 
 	return value, true
 }
+
+func (suite *LocalConfigStoreSuite) TestNewLocalConfigStoreValidatesConfigs() {
+	datafile := filepath.Join(suite.T().TempDir(), "datafile.json")
+	suite.Require().NoError(os.WriteFile(datafile, []byte(`{
+		"configServicePointer": {"projectEnvId": "1"},
+		"configs": [
+			{
+				"key": "settings-schema",
+				"configType": "SCHEMA",
+				"rows": [{"values": [{"value": {"schema": {"schema": "{\"type\": \"object\", \"required\": [\"name\"]}", "schemaType": "JSON_SCHEMA"}}}]}]
+			},
+			{
+				"key": "settings",
+				"schemaKey": "settings-schema",
+				"rows": [{"values": [{"value": {"json": {"json": "{}"}}}]}]
+			}
+		]
+	}`), 0o600))
+
+	_, err := stores.NewLocalConfigStore(datafile)
+	suite.Require().ErrorIs(err, internal.ErrSchemaValidation)
+}
@@ -45,6 +45,10 @@ func NewMemoryConfigStore(projectEnvID int64, rawConfigs map[string]interface{})
 		configs[key] = &config
 	}
 
+	if err := validateConfigs(configs, projectEnvID); err != nil {
+		return nil, err
+	}
+
 	return &MemoryConfigStore{
 		ProjectEnvID: projectEnvID,
 		configMap:    configs,
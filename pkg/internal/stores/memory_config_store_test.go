@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
 	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
 
@@ -181,3 +182,23 @@ func TestNewMemoryConfigStore_WithInvalidValue(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to create config value for key invalid_key")
 	assert.Nil(t, store)
 }
+
+func TestNewMemoryConfigStore_WithDisallowedValue(t *testing.T) {
+	// Arrange
+	config := &prefabProto.Config{
+		Key: "color",
+		AllowableValues: []*prefabProto.ConfigValue{
+			{Type: &prefabProto.ConfigValue_String_{String_: "red"}},
+		},
+		Rows: []*prefabProto.ConfigRow{{Values: []*prefabProto.ConditionalValue{{
+			Value: &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_String_{String_: "green"}},
+		}}}},
+	}
+
+	// Act
+	store, err := NewMemoryConfigStore(123, map[string]interface{}{"color": config})
+
+	// Assert
+	require.ErrorIs(t, err, internal.ErrValueNotAllowed)
+	assert.Nil(t, store)
+}
@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"sync"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/contexts"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// Stats counts telemetry dropped because the queue was full since the
+// submitter was created.
+type Stats struct {
+	DroppedEvaluations uint64
+	DroppedContexts    uint64
+	DroppedLogs        uint64
+}
+
+// Dropped is the total number of dropped items.
+func (s Stats) Dropped() uint64 {
+	return s.DroppedEvaluations + s.DroppedContexts + s.DroppedLogs
+}
+
+// ClientStatsAggregator records items dropped from the telemetry queue and
+// reports them as ClientStats.
+type ClientStatsAggregator struct {
+	name      string
+	dropped   uint64
+	dataStart int64
+	totals    Stats
+	mutex     *sync.Mutex
+}
+
+func NewClientStatsAggregator() *ClientStatsAggregator {
+	return &ClientStatsAggregator{
+		name:  "ClientStatsAggregator",
+		mutex: &sync.Mutex{},
+	}
+}
+
+func (csa *ClientStatsAggregator) Lock() {
+	csa.mutex.Lock()
+}
+
+func (csa *ClientStatsAggregator) Unlock() {
+	csa.mutex.Unlock()
+}
+
+// Record counts item, which was dropped instead of being queued.
+func (csa *ClientStatsAggregator) Record(item interface{}) {
+	csa.Lock()
+	defer csa.Unlock()
+
+	if csa.dataStart == 0 {
+		csa.dataStart = NowProvider()
+	}
+
+	csa.dropped++
+
+	switch item.(type) {
+	case internal.ConfigMatch:
+		csa.totals.DroppedEvaluations++
+	case *contexts.ContextSet:
+		csa.totals.DroppedContexts++
+	case LogRecord:
+		csa.totals.DroppedLogs++
+	}
+}
+
+// Stats returns the drops counted since the aggregator was created; unlike
+// the reported window they aren't reset by Clear.
+func (csa *ClientStatsAggregator) Stats() Stats {
+	csa.Lock()
+	defer csa.Unlock()
+
+	return csa.totals
+}
+
+func (csa *ClientStatsAggregator) GetData() *prefabProto.TelemetryEvent {
+	if csa.dropped == 0 {
+		return nil
+	}
+
+	return &prefabProto.TelemetryEvent{
+		Payload: &prefabProto.TelemetryEvent_ClientStats{
+			ClientStats: &prefabProto.ClientStats{
+				Start:             csa.dataStart,
+				End:               NowProvider(),
+				DroppedEventCount: csa.dropped,
+			},
+		},
+	}
+}
+
+func (csa *ClientStatsAggregator) Clear() {
+	csa.dropped = 0
+	csa.dataStart = 0
+}
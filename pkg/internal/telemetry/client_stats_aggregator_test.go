@@ -0,0 +1,61 @@
+package telemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/contexts"
+	integrationtestsupport "github.com/prefab-cloud/prefab-cloud-go/pkg/internal/integration_test_support"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/telemetry"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestClientStatsAggregator_Record(t *testing.T) {
+	csa := telemetry.NewClientStatsAggregator()
+
+	assert.Nil(t, csa.GetData())
+
+	integrationtestsupport.MockNowProvider()
+
+	csa.Record(internal.ConfigMatch{})
+	csa.Record(internal.ConfigMatch{})
+	csa.Record(contexts.NewContextSet())
+	csa.Record(telemetry.LogRecord{LoggerName: "app"})
+
+	expectedData := &prefabProto.TelemetryEvent{
+		Payload: &prefabProto.TelemetryEvent_ClientStats{
+			ClientStats: &prefabProto.ClientStats{
+				Start:             1,
+				End:               2,
+				DroppedEventCount: 4,
+			},
+		},
+	}
+
+	testutils.AssertJSONEqual(t, expectedData, csa.GetData())
+
+	csa.Clear()
+
+	assert.Nil(t, csa.GetData())
+	assert.Equal(t, telemetry.Stats{DroppedEvaluations: 2, DroppedContexts: 1, DroppedLogs: 1}, csa.Stats())
+	assert.Equal(t, uint64(4), csa.Stats().Dropped())
+}
+
+func TestSubmitterCountsDroppedRecords(t *testing.T) {
+	opts := options.GetDefaultOptions()
+	opts.ContextTelemetryMode = options.ContextTelemetryModes.Shapes
+
+	// Without a queue consumer nothing drains the queue, so everything past
+	// its capacity is dropped.
+	submitter := telemetry.NewTelemetrySubmitter(opts)
+
+	for range 10_003 {
+		submitter.RecordContext(contexts.NewContextSet())
+	}
+
+	assert.Equal(t, telemetry.Stats{DroppedContexts: 3}, submitter.Stats())
+}
@@ -43,7 +43,7 @@ func (esa *EvaluationSummaryAggregator) Record(data interface{}) {
 
 	evaluation := data.(internal.ConfigMatch)
 
-	key := fmt.Sprintf("%d-%d-%d-%d-%s", evaluation.ConfigID, intUnlessNil(evaluation.RowIndex), intUnlessNil(evaluation.ConditionalValueIndex), intUnlessNil(evaluation.WeightedValueIndex), evaluation.Match.String())
+	key := fmt.Sprintf("%d-%d-%d-%d-%d-%s", evaluation.ConfigID, intUnlessNil(evaluation.RowIndex), intUnlessNil(evaluation.ConditionalValueIndex), intUnlessNil(evaluation.WeightedValueIndex), evaluation.Reason.CounterReason(), evaluation.Match.String())
 
 	if _, ok := esa.data[key]; !ok {
 		esa.data[key] = evaluation
@@ -77,6 +77,7 @@ func (esa *EvaluationSummaryAggregator) GetData() *prefabProto.TelemetryEvent {
 			WeightedValueIndex:    coerceToUint32PtrUnlessNil(evaluation.WeightedValueIndex),
 			SelectedValue:         evaluation.Match,
 			Count:                 esa.counts[groupingKey],
+			Reason:                evaluation.Reason.CounterReason(),
 		}
 
 		counterLookup[key][evaluation.ConfigType] = append(counterLookup[key][evaluation.ConfigType], counter)
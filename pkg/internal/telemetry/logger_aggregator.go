@@ -0,0 +1,128 @@
+package telemetry
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// LogRecord is a single log call at Level by the logger named LoggerName.
+type LogRecord struct {
+	LoggerName string
+	Level      prefabProto.LogLevel
+}
+
+type loggerCounts struct {
+	traces, debugs, infos, warns, errors, fatals int64
+}
+
+// LoggerAggregator counts log calls per logger and level.
+type LoggerAggregator struct {
+	name      string
+	data      map[string]*loggerCounts
+	dataStart int64
+	mutex     *sync.Mutex
+}
+
+func NewLoggerAggregator() *LoggerAggregator {
+	return &LoggerAggregator{
+		name:  "LoggerAggregator",
+		data:  make(map[string]*loggerCounts),
+		mutex: &sync.Mutex{},
+	}
+}
+
+func (la *LoggerAggregator) Lock() {
+	la.mutex.Lock()
+}
+
+func (la *LoggerAggregator) Unlock() {
+	la.mutex.Unlock()
+}
+
+func (la *LoggerAggregator) Record(data interface{}) {
+	la.Lock()
+	defer la.Unlock()
+
+	record := data.(LogRecord)
+
+	if la.dataStart == 0 {
+		la.dataStart = NowProvider()
+	}
+
+	counts, ok := la.data[record.LoggerName]
+	if !ok {
+		counts = &loggerCounts{}
+		la.data[record.LoggerName] = counts
+	}
+
+	switch record.Level {
+	case prefabProto.LogLevel_TRACE:
+		counts.traces++
+	case prefabProto.LogLevel_DEBUG:
+		counts.debugs++
+	case prefabProto.LogLevel_INFO:
+		counts.infos++
+	case prefabProto.LogLevel_WARN:
+		counts.warns++
+	case prefabProto.LogLevel_ERROR:
+		counts.errors++
+	case prefabProto.LogLevel_FATAL:
+		counts.fatals++
+	case prefabProto.LogLevel_NOT_SET_LOG_LEVEL:
+	}
+}
+
+func (la *LoggerAggregator) GetData() *prefabProto.TelemetryEvent {
+	if len(la.data) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(la.data))
+	for name := range la.data {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	loggers := make([]*prefabProto.Logger, 0, len(names))
+
+	for _, name := range names {
+		counts := la.data[name]
+
+		loggers = append(loggers, &prefabProto.Logger{
+			LoggerName: name,
+			Traces:     int64PtrUnlessZero(counts.traces),
+			Debugs:     int64PtrUnlessZero(counts.debugs),
+			Infos:      int64PtrUnlessZero(counts.infos),
+			Warns:      int64PtrUnlessZero(counts.warns),
+			Errors:     int64PtrUnlessZero(counts.errors),
+			Fatals:     int64PtrUnlessZero(counts.fatals),
+		})
+	}
+
+	return &prefabProto.TelemetryEvent{
+		Payload: &prefabProto.TelemetryEvent_Loggers{
+			Loggers: &prefabProto.LoggersTelemetryEvent{
+				Loggers: loggers,
+				StartAt: la.dataStart,
+				EndAt:   NowProvider(),
+			},
+		},
+	}
+}
+
+func (la *LoggerAggregator) Clear() {
+	la.data = make(map[string]*loggerCounts)
+	la.dataStart = 0
+}
+
+func int64PtrUnlessZero(value int64) *int64 {
+	if value == 0 {
+		return nil
+	}
+
+	return internal.Int64Ptr(value)
+}
@@ -0,0 +1,60 @@
+package telemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	integrationtestsupport "github.com/prefab-cloud/prefab-cloud-go/pkg/internal/integration_test_support"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/telemetry"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestLoggerAggregator_Record(t *testing.T) {
+	la := telemetry.NewLoggerAggregator()
+
+	assert.Nil(t, la.GetData())
+
+	integrationtestsupport.MockNowProvider()
+
+	for i := 1; i <= 3; i++ {
+		la.Record(telemetry.LogRecord{LoggerName: "app.db", Level: prefabProto.LogLevel_DEBUG})
+	}
+
+	la.Record(telemetry.LogRecord{LoggerName: "app.db", Level: prefabProto.LogLevel_ERROR})
+	la.Record(telemetry.LogRecord{LoggerName: "app", Level: prefabProto.LogLevel_INFO})
+	la.Record(telemetry.LogRecord{LoggerName: "app", Level: prefabProto.LogLevel_TRACE})
+	la.Record(telemetry.LogRecord{LoggerName: "app", Level: prefabProto.LogLevel_FATAL})
+	la.Record(telemetry.LogRecord{LoggerName: "app", Level: prefabProto.LogLevel_WARN})
+
+	expectedData := &prefabProto.TelemetryEvent{
+		Payload: &prefabProto.TelemetryEvent_Loggers{
+			Loggers: &prefabProto.LoggersTelemetryEvent{
+				StartAt: 1,
+				EndAt:   2,
+				Loggers: []*prefabProto.Logger{
+					{
+						LoggerName: "app",
+						Traces:     internal.Int64Ptr(1),
+						Infos:      internal.Int64Ptr(1),
+						Warns:      internal.Int64Ptr(1),
+						Fatals:     internal.Int64Ptr(1),
+					},
+					{
+						LoggerName: "app.db",
+						Debugs:     internal.Int64Ptr(3),
+						Errors:     internal.Int64Ptr(1),
+					},
+				},
+			},
+		},
+	}
+
+	testutils.AssertJSONEqual(t, expectedData, la.GetData())
+
+	la.Clear()
+
+	assert.Nil(t, la.GetData())
+}
@@ -2,6 +2,7 @@ package telemetry
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -17,10 +18,10 @@ import (
 	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
 
-var (
-	NowProvider = time.Now().UnixMilli
-	client      = &http.Client{}
-)
+var NowProvider = time.Now().UnixMilli
+
+// queueSize is how many records can wait for the consumer before new ones are dropped.
+const queueSize = 10000
 
 type QueueItem interface{}
 
@@ -28,11 +29,18 @@ type Submitter struct {
 	aggregators                 []Aggregator
 	contextAggregators          []Aggregator
 	evaluationSummaryAggregator *EvaluationSummaryAggregator
+	loggerAggregator            *LoggerAggregator
+	clientStatsAggregator       *ClientStatsAggregator
 	instanceHash                string
+	httpClient                  *http.Client
 	host                        string
 	apiKey                      string
 	mutex                       *sync.Mutex
 	queue                       chan QueueItem
+	stop                        chan struct{}
+	closeMutex                  *sync.RWMutex
+	workers                     *sync.WaitGroup
+	closed                      bool
 }
 
 type Payload = prefabProto.TelemetryEvents
@@ -52,28 +60,52 @@ func NewTelemetrySubmitter(options options.Options) *Submitter {
 		aggregators = append(aggregators, evaluationSummaryAggregator)
 	}
 
+	var loggerAggregator *LoggerAggregator
+	if options.CollectLoggerCounts {
+		loggerAggregator = NewLoggerAggregator()
+		aggregators = append(aggregators, loggerAggregator)
+	}
+
+	// Records are only queued for the aggregators above, so drops are only
+	// reported if at least one of them is enabled.
+	clientStatsAggregator := NewClientStatsAggregator()
+	if len(aggregators) > 0 {
+		aggregators = append(aggregators, clientStatsAggregator)
+	}
+
 	return &Submitter{
 		aggregators:                 aggregators,
+		httpClient:                  options.HTTPClientOrDefault(),
 		host:                        options.TelemetryHost,
 		apiKey:                      options.APIKey,
 		contextAggregators:          contextAggregators,
 		evaluationSummaryAggregator: evaluationSummaryAggregator,
+		loggerAggregator:            loggerAggregator,
+		clientStatsAggregator:       clientStatsAggregator,
 		mutex:                       &sync.Mutex{},
 		instanceHash:                options.InstanceHash,
-		queue:                       make(chan QueueItem, 10000),
+		queue:                       make(chan QueueItem, queueSize),
+		stop:                        make(chan struct{}),
+		closeMutex:                  &sync.RWMutex{},
+		workers:                     &sync.WaitGroup{},
 	}
 }
 
 func (ts *Submitter) SetupQueueConsumer() {
+	ts.workers.Add(1)
+
 	go func() {
-		for {
-			for item := range ts.queue {
-				switch item := item.(type) {
-				case internal.ConfigMatch:
-					ts.internalRecordEvaluation(item)
-				case *contexts.ContextSet:
-					ts.internalRecordContext(item)
-				}
+		defer ts.workers.Done()
+
+		// The queue is only closed by Shutdown, so this runs until then.
+		for item := range ts.queue {
+			switch item := item.(type) {
+			case internal.ConfigMatch:
+				ts.internalRecordEvaluation(item)
+			case *contexts.ContextSet:
+				ts.internalRecordContext(item)
+			case LogRecord:
+				ts.loggerAggregator.Record(item)
 			}
 		}
 	}()
@@ -84,24 +116,74 @@ func (ts *Submitter) StartPeriodicSubmission(interval time.Duration) {
 
 	ticker := time.NewTicker(interval)
 
+	ts.workers.Add(1)
+
 	go func() {
-		for range ticker.C {
-			if len(ts.aggregators) > 0 {
-				ts.Submit(false)
+		defer ts.workers.Done()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ts.stop:
+				return
+			case <-ticker.C:
+				if len(ts.aggregators) > 0 {
+					ts.Submit(false)
+				}
 			}
 		}
 	}()
 }
 
+// Shutdown stops the periodic submission and queue consumer goroutines,
+// drains anything still queued into the aggregators and performs a final
+// submission. Records arriving after Shutdown has been called are dropped.
+// Calling Shutdown more than once is a no-op.
+func (ts *Submitter) Shutdown(ctx context.Context) error {
+	ts.closeMutex.Lock()
+
+	if ts.closed {
+		ts.closeMutex.Unlock()
+
+		return nil
+	}
+
+	ts.closed = true
+	close(ts.stop)
+	close(ts.queue)
+	ts.closeMutex.Unlock()
+
+	ts.workers.Wait()
+
+	if len(ts.aggregators) == 0 {
+		return nil
+	}
+
+	return ts.submit(ctx, false)
+}
+
 func (ts *Submitter) enqueue(item QueueItem) {
+	ts.closeMutex.RLock()
+	defer ts.closeMutex.RUnlock()
+
+	if ts.closed {
+		return
+	}
+
 	select {
 	case ts.queue <- item:
 		// Successfully enqueued
 	default:
 		// Queue is full, drop the item
+		ts.clientStatsAggregator.Record(item)
 	}
 }
 
+// Stats returns how many records have been dropped because the queue was full.
+func (ts *Submitter) Stats() Stats {
+	return ts.clientStatsAggregator.Stats()
+}
+
 func (ts *Submitter) RecordEvaluation(data internal.ConfigMatch) {
 	if ts.evaluationSummaryAggregator == nil || !data.IsMatch {
 		return
@@ -133,7 +215,20 @@ func (ts *Submitter) internalRecordContext(data *contexts.ContextSet) {
 	}
 }
 
+// RecordLog counts a log call by loggerName at level.
+func (ts *Submitter) RecordLog(loggerName string, level prefabProto.LogLevel) {
+	if ts.loggerAggregator == nil {
+		return
+	}
+
+	ts.enqueue(LogRecord{LoggerName: loggerName, Level: level})
+}
+
 func (ts *Submitter) Submit(waitOnQueueToDrain bool) error {
+	return ts.submit(context.Background(), waitOnQueueToDrain)
+}
+
+func (ts *Submitter) submit(ctx context.Context, waitOnQueueToDrain bool) error {
 	ts.mutex.Lock()
 	defer ts.mutex.Unlock()
 
@@ -174,7 +269,7 @@ func (ts *Submitter) Submit(waitOnQueueToDrain bool) error {
 		return fmt.Errorf("failed to marshal payload: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(payloadData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payloadData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
@@ -186,17 +281,17 @@ func (ts *Submitter) Submit(waitOnQueueToDrain bool) error {
 	encodedAuth := base64.StdEncoding.EncodeToString([]byte("authuser:" + ts.apiKey))
 	req.Header.Set("Authorization", "Basic "+encodedAuth)
 
-	return ts.retryRequest(req)
+	return ts.retryRequest(ctx, req)
 }
 
 // retryRequest attempts an HTTP request with retries and exponential backoff
-func (ts *Submitter) retryRequest(req *http.Request) error {
+func (ts *Submitter) retryRequest(ctx context.Context, req *http.Request) error {
 	const maxRetries = 5
 
 	backoff := 1 * time.Second
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		resp, err := client.Do(req)
+		resp, err := ts.httpClient.Do(req)
 
 		if err == nil && resp.StatusCode == http.StatusOK {
 			defer resp.Body.Close()
@@ -215,7 +310,12 @@ func (ts *Submitter) retryRequest(req *http.Request) error {
 			return fmt.Errorf("telemetry submission failed with status %s after %d attempts", resp.Status, attempt)
 		}
 
-		time.Sleep(backoff)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("telemetry submission abandoned after %d attempts: %w", attempt, ctx.Err())
+		case <-time.After(backoff):
+		}
+
 		backoff *= 2
 	}
 
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"os"
 	"reflect"
 	"strconv"
 	"strings"
@@ -78,6 +77,10 @@ func Create(value any) (*prefabProto.ConfigValue, bool) {
 
 // ExtractValue returns the value of the oneof field and a boolean indicating
 // if it's one of the simple types (fields 1-5, 10). returns nil, false if the configvalue is nil
+//
+// Provided values are read from environment variables with os.LookupEnv. The
+// client doesn't rely on this: it resolves them with its ProvidedResolver when
+// the config is evaluated, so the values it returns are never Provided.
 func ExtractValue(cv *prefabProto.ConfigValue) (any, bool, error) {
 	if cv == nil {
 		return nil, false, nil
@@ -110,7 +113,7 @@ func ExtractValue(cv *prefabProto.ConfigValue) (any, bool, error) {
 
 		return nil, false, nil
 	case *prefabProto.ConfigValue_Provided:
-		val, ok := handleProvided(v.Provided)
+		val, ok := EnvProvidedResolver{}.ResolveProvided(v.Provided)
 
 		return val, ok, nil
 	default:
@@ -119,18 +122,6 @@ func ExtractValue(cv *prefabProto.ConfigValue) (any, bool, error) {
 	}
 }
 
-func handleProvided(provided *prefabProto.Provided) (string, bool) {
-	if provided.GetSource() == prefabProto.ProvidedSource_ENV_VAR {
-		if provided.Lookup != nil {
-			envValue, envValueExists := os.LookupEnv(provided.GetLookup())
-
-			return envValue, envValueExists
-		}
-	}
-
-	return "", false
-}
-
 // GetValueType returns the value type we'd expect the config containing this value to have
 func GetValueType(cv *prefabProto.ConfigValue) prefabProto.Config_ValueType {
 	if cv == nil {
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// ProvidedResolver looks up the value of a Provided config value (one that
+// lives outside Prefab, e.g. in an environment variable or a secret file).
+type ProvidedResolver interface {
+	ResolveProvided(provided *prefabProto.Provided) (value string, exists bool)
+}
+
+// EnvProvidedResolver resolves ENV_VAR provided values with LookupEnv, or
+// os.LookupEnv if it is nil.
+type EnvProvidedResolver struct {
+	LookupEnv func(key string) (string, bool)
+}
+
+func (r EnvProvidedResolver) ResolveProvided(provided *prefabProto.Provided) (string, bool) {
+	if provided.GetSource() != prefabProto.ProvidedSource_ENV_VAR || provided.Lookup == nil {
+		return "", false
+	}
+
+	lookupEnv := r.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	return lookupEnv(provided.GetLookup())
+}
+
+// FileProvidedResolver resolves provided values from files, such as mounted
+// secrets. A lookup listed in Files is read from that path; any other lookup
+// is read from the file of the same name in Dir (if Dir is set). Trailing
+// newlines are trimmed.
+type FileProvidedResolver struct {
+	Files map[string]string
+	Dir   string
+}
+
+func (r FileProvidedResolver) ResolveProvided(provided *prefabProto.Provided) (string, bool) {
+	if provided.Lookup == nil {
+		return "", false
+	}
+
+	path, listed := r.Files[provided.GetLookup()]
+	if !listed {
+		if r.Dir == "" || !filepath.IsLocal(provided.GetLookup()) {
+			return "", false
+		}
+
+		path = filepath.Join(r.Dir, provided.GetLookup())
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("unable to read provided value", "lookup", provided.GetLookup(), "path", path, "err", err)
+		}
+
+		return "", false
+	}
+
+	return strings.TrimRight(string(contents), "\r\n"), true
+}
+
+// MapProvidedResolver resolves provided values from an in-memory map keyed by
+// lookup, e.g. as a stand-in for a secrets vault in tests.
+type MapProvidedResolver map[string]string
+
+func (r MapProvidedResolver) ResolveProvided(provided *prefabProto.Provided) (string, bool) {
+	if provided.Lookup == nil {
+		return "", false
+	}
+
+	value, exists := r[provided.GetLookup()]
+
+	return value, exists
+}
+
+// ProvidedResolverChain asks each resolver in turn, returning the first value
+// found.
+type ProvidedResolverChain []ProvidedResolver
+
+func (c ProvidedResolverChain) ResolveProvided(provided *prefabProto.Provided) (string, bool) {
+	for _, resolver := range c {
+		if value, exists := resolver.ResolveProvided(provided); exists {
+			return value, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,101 @@
+package utils_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/utils"
+
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func envVar(lookup string) *prefabProto.Provided {
+	return &prefabProto.Provided{Source: prefabProto.ProvidedSource_ENV_VAR.Enum(), Lookup: &lookup}
+}
+
+func TestEnvProvidedResolver(t *testing.T) {
+	resolver := utils.EnvProvidedResolver{LookupEnv: func(key string) (string, bool) {
+		return "value of " + key, key == "SET"
+	}}
+
+	value, exists := resolver.ResolveProvided(envVar("SET"))
+	assert.True(t, exists)
+	assert.Equal(t, "value of SET", value)
+
+	_, exists = resolver.ResolveProvided(envVar("UNSET"))
+	assert.False(t, exists)
+
+	_, exists = resolver.ResolveProvided(&prefabProto.Provided{Lookup: envVar("SET").Lookup})
+	assert.False(t, exists, "only environment variables are looked up")
+
+	t.Setenv("PREFAB_PROVIDED_RESOLVER_TEST", "from the environment")
+
+	value, exists = utils.EnvProvidedResolver{}.ResolveProvided(envVar("PREFAB_PROVIDED_RESOLVER_TEST"))
+	assert.True(t, exists)
+	assert.Equal(t, "from the environment", value)
+}
+
+func TestFileProvidedResolver(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db_password"), []byte("hunter2\n"), 0o600))
+
+	single := filepath.Join(t.TempDir(), "api-token")
+	require.NoError(t, os.WriteFile(single, []byte("token"), 0o600))
+
+	resolver := utils.FileProvidedResolver{Dir: dir, Files: map[string]string{"API_TOKEN": single}}
+
+	tests := []struct {
+		name     string
+		lookup   string
+		expected string
+		exists   bool
+	}{
+		{"reads files in the directory", "db_password", "hunter2", true},
+		{"reads listed files", "API_TOKEN", "token", true},
+		{"missing files don't exist", "missing", "", false},
+		{"lookups can't leave the directory", "../api-token", "", false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, exists := resolver.ResolveProvided(envVar(testCase.lookup))
+			assert.Equal(t, testCase.exists, exists)
+			assert.Equal(t, testCase.expected, value)
+		})
+	}
+}
+
+func TestProvidedResolverChain(t *testing.T) {
+	chain := utils.ProvidedResolverChain{
+		utils.MapProvidedResolver{"A": "first"},
+		utils.MapProvidedResolver{"A": "second", "B": "second"},
+	}
+
+	value, exists := chain.ResolveProvided(envVar("A"))
+	assert.True(t, exists)
+	assert.Equal(t, "first", value)
+
+	value, exists = chain.ResolveProvided(envVar("B"))
+	assert.True(t, exists)
+	assert.Equal(t, "second", value)
+
+	_, exists = chain.ResolveProvided(envVar("C"))
+	assert.False(t, exists)
+}
+
+func TestExtractValueReadsProvidedValuesFromTheEnvironment(t *testing.T) {
+	t.Setenv("SECRET", "shh")
+
+	value, ok, err := utils.ExtractValue(&prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Provided{Provided: envVar("SECRET")}})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "shh", value)
+
+	_, ok, err = utils.ExtractValue(&prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Provided{Provided: envVar("UNSET")}})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,60 @@
+package prefab_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestJSONSchemaValidation(t *testing.T) {
+	jsonConfig := func(key string, id int64, json string) *prefabProto.Config {
+		config := testConfig(key, id, prefabProto.ConfigType_CONFIG,
+			always(&prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Json{Json: &prefabProto.Json{Json: json}}}))
+		config.SchemaKey = internal.StringPtr("settings-schema")
+
+		return config
+	}
+
+	schema := testConfig("settings-schema", 1, prefabProto.ConfigType_SCHEMA,
+		always(&prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Schema{Schema: &prefabProto.Schema{
+			Schema:     `{"type": "object", "required": ["name"]}`,
+			SchemaType: prefabProto.Schema_JSON_SCHEMA,
+		}}}))
+
+	client, updates := newTestClient(t,
+		schema,
+		jsonConfig("settings", 2, `{"name": "a"}`),
+		jsonConfig("broken", 3, `{"other": "a"}`))
+
+	value, ok, err := client.GetJSONValue("settings", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"name": "a"}, value)
+
+	// new configs are checked too, so broken was never loaded
+	_, ok, err = client.GetJSONValue("broken", prefab.ContextSet{})
+	require.ErrorIs(t, err, prefab.ErrConfigDoesNotExist)
+	assert.False(t, ok)
+
+	events, stop := client.Watch("other")
+	defer stop()
+
+	sendUpdate(t, updates, &prefabProto.Configs{Configs: []*prefabProto.Config{jsonConfig("settings", 4, `{}`), stringConfig(t, "other", 5, "x")}})
+
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the update")
+	}
+
+	value, ok, err = client.GetJSONValue("settings", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"name": "a"}, value, "the invalid update is ignored")
+}
@@ -0,0 +1,164 @@
+package prefab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/contexts"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/ratelimit"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// ErrNotALimitDefinition is returned by Acquire when the limit key's config
+// isn't a LIMIT_DEFINITION.
+var ErrNotALimitDefinition = errors.New("config is not a limit definition")
+
+// ErrInvalidAmount is returned by Acquire when amount isn't positive.
+var ErrInvalidAmount = ratelimit.ErrInvalidAmount
+
+// Acquire takes amount permits from the limit defined by the
+// LIMIT_DEFINITION config limitKey. Every group (e.g. a user or account ID)
+// has its own bucket under limitKey and the request only passes if all of them
+// have enough permits left; with no groups the whole key shares one bucket.
+// The definition is evaluated with the global context.
+//
+// Limits are enforced in-process, so each client instance enforces its own
+// copy of the limit whatever the definition's safety level.
+func (c *Client) Acquire(ctx context.Context, limitKey string, groups []string, amount int32) (*prefabProto.LimitResponse, error) {
+	return c.boundClient.Acquire(ctx, limitKey, groups, amount)
+}
+
+// Acquire is like Client.Acquire, evaluating the definition with the bound context.
+func (c *ContextBoundClient) Acquire(ctx context.Context, limitKey string, groups []string, amount int32) (*prefabProto.LimitResponse, error) {
+	response, _, err := c.acquire(ctx, limitKey, groups, amount)
+
+	return response, err
+}
+
+// acquire is Acquire, also returning how long until a rejected request could pass.
+func (c *ContextBoundClient) acquire(ctx context.Context, limitKey string, groups []string, amount int32) (*prefabProto.LimitResponse, time.Duration, error) {
+	if amount <= 0 {
+		return nil, 0, fmt.Errorf("%w: %d", ErrInvalidAmount, amount)
+	}
+
+	if err := c.client.awaitEvaluable(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	match, err := c.client.configResolver.ResolveValue(limitKey, contexts.Merge(c.context))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	definition := match.Match.GetLimitDefinition()
+	if definition == nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrNotALimitDefinition, limitKey)
+	}
+
+	policy, err := limitPolicy(definition)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", limitKey, err)
+	}
+
+	bucketKeys := []string{limitKey}
+	if len(groups) > 0 {
+		bucketKeys = make([]string, len(groups))
+		for i, group := range groups {
+			bucketKeys[i] = limitKey + ":" + group
+		}
+	}
+
+	result, err := c.client.limiter.Acquire(policy, bucketKeys, amount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	response := &prefabProto.LimitResponse{
+		Passed:        result.Passed,
+		EnforcedGroup: result.EnforcedGroup,
+		CurrentBucket: result.Remaining,
+		PolicyGroup:   limitKey,
+		PolicyName:    definition.GetPolicyName(),
+		PolicyLimit:   definition.GetLimit(),
+		SafetyLevel:   definition.GetSafetyLevel(),
+	}
+
+	if result.Passed {
+		response.Amount = int64(amount)
+	}
+
+	if !result.ResetAt.IsZero() {
+		response.LimitResetAt = result.ResetAt.UnixMilli()
+	}
+
+	return response, result.RetryAfter, nil
+}
+
+func limitPolicy(definition *prefabProto.LimitDefinition) (ratelimit.Policy, error) {
+	policy := ratelimit.Policy{Limit: definition.GetLimit(), Burst: definition.GetBurst()}
+
+	switch definition.GetPolicyName() {
+	case prefabProto.LimitResponse_SECONDLY_ROLLING:
+		policy.Window = time.Second
+	case prefabProto.LimitResponse_MINUTELY_ROLLING:
+		policy.Window = time.Minute
+	case prefabProto.LimitResponse_HOURLY_ROLLING:
+		policy.Window = time.Hour
+	case prefabProto.LimitResponse_DAILY_ROLLING:
+		policy.Window = 24 * time.Hour
+	case prefabProto.LimitResponse_MONTHLY_ROLLING:
+		policy.Window = 30 * 24 * time.Hour
+	case prefabProto.LimitResponse_YEARLY_ROLLING:
+		policy.Window = 365 * 24 * time.Hour
+	case prefabProto.LimitResponse_INFINITE:
+	case prefabProto.LimitResponse_NOT_SET:
+		return policy, errors.New("limit definition has no policy")
+	default:
+		return policy, fmt.Errorf("unsupported limit policy %s", definition.GetPolicyName())
+	}
+
+	return policy, nil
+}
+
+// LimitMiddleware returns net/http middleware that acquires one permit from
+// limitKey for every request, grouped by whatever groups returns for it (nil
+// groups are fine). Rejected requests get a 429 with a Retry-After header
+// saying when a permit will be available. If the limit can't be evaluated
+// (e.g. the config is missing) requests are let through and the error is
+// logged.
+func (c *Client) LimitMiddleware(limitKey string, groups func(*http.Request) []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var requestGroups []string
+			if groups != nil {
+				requestGroups = groups(r)
+			}
+
+			response, retryAfter, err := c.boundClient.acquire(r.Context(), limitKey, requestGroups, 1)
+			if err != nil {
+				slog.Warn("unable to apply limit, allowing request", "key", limitKey, "err", err)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if !response.GetPassed() {
+				if retryAfter > 0 {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				}
+
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package prefab_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func limitDefinitionValue(definition *prefabProto.LimitDefinition) *prefabProto.ConfigValue {
+	return &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_LimitDefinition{LimitDefinition: definition}}
+}
+
+func newLimitsClient(t *testing.T) *prefab.Client {
+	t.Helper()
+
+	isPro := &prefabProto.Criterion{
+		PropertyName: "user.plan",
+		Operator:     prefabProto.Criterion_PROP_IS_ONE_OF,
+		ValueToMatch: testutils.CreateConfigValueAndAssertOk(t, []string{"pro"}),
+	}
+
+	client, _ := newTestClient(t,
+		testConfig("api.requests", 1, prefabProto.ConfigType_LIMIT_DEFINITION, always(limitDefinitionValue(&prefabProto.LimitDefinition{
+			PolicyName:  prefabProto.LimitResponse_HOURLY_ROLLING,
+			Limit:       2,
+			SafetyLevel: prefabProto.LimitDefinition_L4_BEST_EFFORT,
+		}))),
+		testConfig("tiered.requests", 2, prefabProto.ConfigType_LIMIT_DEFINITION,
+			&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{isPro}, Value: limitDefinitionValue(&prefabProto.LimitDefinition{
+				PolicyName: prefabProto.LimitResponse_HOURLY_ROLLING,
+				Limit:      5,
+			})},
+			always(limitDefinitionValue(&prefabProto.LimitDefinition{
+				PolicyName: prefabProto.LimitResponse_HOURLY_ROLLING,
+				Limit:      1,
+			}))),
+		testConfig("not.a.limit", 3, prefabProto.ConfigType_CONFIG, always(testutils.CreateConfigValueAndAssertOk(t, "x"))))
+
+	return client
+}
+
+func TestAcquire(t *testing.T) {
+	client := newLimitsClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := client.Acquire(ctx, "api.requests", []string{"user-1"}, 2)
+	require.NoError(t, err)
+	assert.True(t, response.GetPassed())
+	assert.Equal(t, "api.requests:user-1", response.GetEnforcedGroup())
+	assert.Equal(t, "api.requests", response.GetPolicyGroup())
+	assert.Equal(t, prefabProto.LimitResponse_HOURLY_ROLLING, response.GetPolicyName())
+	assert.Equal(t, int32(2), response.GetPolicyLimit())
+	assert.Equal(t, int64(2), response.GetAmount())
+	assert.Equal(t, int64(0), response.GetCurrentBucket())
+	assert.Equal(t, prefabProto.LimitDefinition_L4_BEST_EFFORT, response.GetSafetyLevel())
+
+	response, err = client.Acquire(ctx, "api.requests", []string{"user-1"}, 1)
+	require.NoError(t, err)
+	assert.False(t, response.GetPassed())
+	assert.Equal(t, int64(0), response.GetAmount())
+	assert.Positive(t, response.GetLimitResetAt())
+
+	response, err = client.Acquire(ctx, "api.requests", []string{"user-2"}, 1)
+	require.NoError(t, err)
+	assert.True(t, response.GetPassed())
+
+	_, err = client.Acquire(ctx, "not.a.limit", nil, 1)
+	require.ErrorIs(t, err, prefab.ErrNotALimitDefinition)
+
+	_, err = client.Acquire(ctx, "does.not.exist", nil, 1)
+	require.ErrorIs(t, err, prefab.ErrConfigDoesNotExist)
+}
+
+func TestAcquireRejectsNonPositiveAmounts(t *testing.T) {
+	client := newLimitsClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, amount := range []int32{0, -1} {
+		_, err := client.Acquire(ctx, "api.requests", nil, amount)
+		require.ErrorIs(t, err, prefab.ErrInvalidAmount)
+	}
+}
+
+func TestAcquireUsesBoundContext(t *testing.T) {
+	client := newLimitsClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := client.Acquire(ctx, "tiered.requests", []string{"free-user"}, 3)
+	require.NoError(t, err)
+	assert.False(t, response.GetPassed())
+	assert.Equal(t, int32(1), response.GetPolicyLimit())
+
+	pro := client.WithContext(prefab.NewContextSet().WithNamedContextValues("user", map[string]interface{}{"plan": "pro"}))
+
+	response, err = pro.Acquire(ctx, "tiered.requests", []string{"pro-user"}, 3)
+	require.NoError(t, err)
+	assert.True(t, response.GetPassed())
+	assert.Equal(t, int32(5), response.GetPolicyLimit())
+}
+
+func TestLimitMiddleware(t *testing.T) {
+	client := newLimitsClient(t)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	byUser := func(r *http.Request) []string {
+		return []string{r.Header.Get("X-User")}
+	}
+
+	limited := client.LimitMiddleware("api.requests", byUser)(ok)
+	unknownLimit := client.LimitMiddleware("does.not.exist", nil)(ok)
+
+	serve := func(handler http.Handler, user string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("X-User", user)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, serve(limited, "a").Code)
+	assert.Equal(t, http.StatusOK, serve(limited, "a").Code)
+
+	rejected := serve(limited, "a")
+	assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	assert.Equal(t, "1800", rejected.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, serve(limited, "b").Code)
+	assert.Equal(t, http.StatusOK, serve(unknownLimit, "a").Code, "requests pass when the limit can't be evaluated")
+}
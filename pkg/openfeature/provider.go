@@ -0,0 +1,383 @@
+// Package openfeature provides an OpenFeature provider backed by a Prefab client.
+package openfeature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+)
+
+const (
+	// ProviderName is reported in the provider's metadata and events.
+	ProviderName = "Prefab"
+
+	defaultContextName  = "user"
+	defaultTargetingKey = "user.key"
+	defaultInitTimeout  = 10 * time.Second
+	eventBufferSize     = 64
+)
+
+// Provider is an OpenFeature FeatureProvider that evaluates flags with a
+// prefab.Client. The client's lifecycle belongs to the caller; shutting the
+// provider down doesn't close it.
+type Provider struct {
+	client               *prefab.Client
+	events               chan of.Event
+	unsubscribe          func()
+	unsubscribeState     func()
+	defaultContextName   string
+	targetingKeyProperty string
+	initTimeout          time.Duration
+	mutex                sync.Mutex
+	subscribedToChanges  bool
+}
+
+// ProviderOption configures a Provider.
+type ProviderOption func(*Provider)
+
+// WithDefaultContextName sets the named context that evaluation context
+// attributes without a dot are added to. Defaults to "user".
+func WithDefaultContextName(name string) ProviderOption {
+	return func(p *Provider) {
+		p.defaultContextName = name
+	}
+}
+
+// WithTargetingKeyProperty sets the context property the OpenFeature targeting
+// key is stored in. Defaults to "user.key".
+func WithTargetingKeyProperty(property string) ProviderOption {
+	return func(p *Provider) {
+		p.targetingKeyProperty = property
+	}
+}
+
+// WithInitTimeout bounds how long Init waits for the client to load its
+// configs. Defaults to 10 seconds.
+func WithInitTimeout(timeout time.Duration) ProviderOption {
+	return func(p *Provider) {
+		p.initTimeout = timeout
+	}
+}
+
+// NewProvider creates a Provider that evaluates flags with client.
+func NewProvider(client *prefab.Client, opts ...ProviderOption) *Provider {
+	provider := &Provider{
+		client:               client,
+		events:               make(chan of.Event, eventBufferSize),
+		defaultContextName:   defaultContextName,
+		targetingKeyProperty: defaultTargetingKey,
+		initTimeout:          defaultInitTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(provider)
+	}
+
+	return provider
+}
+
+// Metadata returns the provider's name.
+func (p *Provider) Metadata() of.Metadata {
+	return of.Metadata{Name: ProviderName}
+}
+
+// Hooks returns no hooks.
+func (p *Provider) Hooks() []of.Hook {
+	return nil
+}
+
+// Init waits for the client to finish loading and starts forwarding config
+// changes as PROVIDER_CONFIGURATION_CHANGED events. Losing the API (the
+// client's connection state becoming failed) is sent as PROVIDER_ERROR, the
+// configs becoming stale (see prefab.WithMaxStaleness) as PROVIDER_STALE, and
+// recovering from either as PROVIDER_READY.
+func (p *Provider) Init(of.EvaluationContext) error {
+	p.mutex.Lock()
+	if !p.subscribedToChanges {
+		p.subscribedToChanges = true
+		p.unsubscribe = p.client.OnConfigUpdate(p.onConfigUpdate)
+		p.unsubscribeState = p.client.OnStateChange(p.onStateChange)
+	}
+	p.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.initTimeout)
+	defer cancel()
+
+	if err := p.client.WaitForReady(ctx); err != nil {
+		return fmt.Errorf("prefab client not ready: %w", err)
+	}
+
+	return nil
+}
+
+// Shutdown stops forwarding config changes.
+func (p *Provider) Shutdown() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.subscribedToChanges {
+		p.unsubscribe()
+		p.unsubscribeState()
+		p.subscribedToChanges = false
+	}
+}
+
+// EventChannel returns the channel provider events are sent on.
+func (p *Provider) EventChannel() <-chan of.Event {
+	return p.events
+}
+
+// onConfigUpdate sends one event per update listing every changed key. The
+// initial load is applied before the client is ready and isn't a change.
+func (p *Provider) onConfigUpdate(events []prefab.ChangeEvent) {
+	if !p.clientReady() {
+		return
+	}
+
+	keys := make([]string, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(keys, event.Key) {
+			keys = append(keys, event.Key)
+		}
+	}
+
+	p.emit(of.Event{
+		ProviderName: ProviderName,
+		EventType:    of.ProviderConfigChange,
+		ProviderEventDetails: of.ProviderEventDetails{
+			Message:     fmt.Sprintf("%d prefab config(s) changed", len(keys)),
+			FlagChanges: keys,
+		},
+	})
+}
+
+// clientReady reports whether the client has finished initializing, without
+// waiting for it.
+func (p *Provider) clientReady() bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	return p.client.WaitForReady(ctx) == nil
+}
+
+func (p *Provider) onStateChange(change prefab.ConnectionStateChange) {
+	switch {
+	case change.Status.State == prefab.ConnectionFailed:
+		message := "prefab client could not load configs"
+		if change.Status.Err != nil {
+			message += ": " + change.Status.Err.Error()
+		}
+
+		p.emit(of.Event{
+			ProviderName:         ProviderName,
+			EventType:            of.ProviderError,
+			ProviderEventDetails: of.ProviderEventDetails{Message: message},
+		})
+	case change.Status.Stale && !change.WasStale:
+		p.emit(of.Event{
+			ProviderName:         ProviderName,
+			EventType:            of.ProviderStale,
+			ProviderEventDetails: of.ProviderEventDetails{Message: "prefab configs are stale"},
+		})
+	case change.Old == prefab.ConnectionFailed && (change.Status.State == prefab.ConnectionLoaded || change.Status.State == prefab.ConnectionStreaming),
+		change.WasStale && !change.Status.Stale:
+		p.emit(of.Event{
+			ProviderName:         ProviderName,
+			EventType:            of.ProviderReady,
+			ProviderEventDetails: of.ProviderEventDetails{Message: "prefab configs are up to date"},
+		})
+	}
+}
+
+// emit sends event without blocking; events are dropped if nobody is reading.
+func (p *Provider) emit(event of.Event) {
+	select {
+	case p.events <- event:
+	default:
+		slog.Warn("dropping OpenFeature provider event, channel is full", "type", event.EventType)
+	}
+}
+
+// BooleanEvaluation resolves a bool flag.
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, flatCtx of.FlattenedContext) of.BoolResolutionDetail {
+	details, ok, err := p.client.GetBoolDetailsCtx(ctx, flag, p.contextSet(flatCtx))
+	value, detail := resolve(details, ok, err, defaultValue)
+
+	return of.BoolResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// StringEvaluation resolves a string flag.
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, flatCtx of.FlattenedContext) of.StringResolutionDetail {
+	details, ok, err := p.client.GetStringDetailsCtx(ctx, flag, p.contextSet(flatCtx))
+	value, detail := resolve(details, ok, err, defaultValue)
+
+	return of.StringResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// FloatEvaluation resolves a float flag.
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, flatCtx of.FlattenedContext) of.FloatResolutionDetail {
+	details, ok, err := p.client.GetFloatDetailsCtx(ctx, flag, p.contextSet(flatCtx))
+	value, detail := resolve(details, ok, err, defaultValue)
+
+	return of.FloatResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// IntEvaluation resolves an int flag.
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, flatCtx of.FlattenedContext) of.IntResolutionDetail {
+	details, ok, err := p.client.GetIntDetailsCtx(ctx, flag, p.contextSet(flatCtx))
+	value, detail := resolve(details, ok, err, defaultValue)
+
+	return of.IntResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// ObjectEvaluation resolves a JSON flag.
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue any, flatCtx of.FlattenedContext) of.InterfaceResolutionDetail {
+	details, ok, err := p.client.GetJSONDetailsCtx(ctx, flag, p.contextSet(flatCtx))
+	value, detail := resolve(details, ok, err, defaultValue)
+
+	return of.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// contextSet converts an OpenFeature context into a ContextSet. The targeting
+// key goes to targetingKeyProperty, "name.property" attributes go to the named
+// context, map attributes become named contexts and anything else goes to
+// the default context.
+func (p *Provider) contextSet(flatCtx of.FlattenedContext) prefab.ContextSet {
+	namedValues := make(map[string]map[string]interface{})
+
+	set := func(property string, value interface{}) {
+		contextName, key := p.defaultContextName, property
+		if index := strings.Index(property, "."); index >= 0 {
+			contextName, key = property[:index], property[index+1:]
+		}
+
+		if namedValues[contextName] == nil {
+			namedValues[contextName] = make(map[string]interface{})
+		}
+
+		namedValues[contextName][key] = value
+	}
+
+	for attribute, value := range flatCtx {
+		if attribute == of.TargetingKey {
+			continue
+		}
+
+		if nested, isMap := value.(map[string]interface{}); isMap && !strings.Contains(attribute, ".") {
+			for key, nestedValue := range nested {
+				set(attribute+"."+key, nestedValue)
+			}
+
+			continue
+		}
+
+		set(attribute, value)
+	}
+
+	// set last so the targeting key wins over an attribute with the same name
+	if targetingKey, ok := flatCtx[of.TargetingKey]; ok && targetingKey != "" {
+		set(p.targetingKeyProperty, targetingKey)
+	}
+
+	contextSet := prefab.NewContextSet()
+	for name, values := range namedValues {
+		contextSet.WithNamedContextValues(name, values)
+	}
+
+	return *contextSet
+}
+
+func resolve[T any](details prefab.EvaluationDetails[T], ok bool, err error, defaultValue T) (T, of.ProviderResolutionDetail) {
+	detail := of.ProviderResolutionDetail{
+		Reason:       reason(details.Reason),
+		Variant:      variant(details),
+		FlagMetadata: flagMetadata(details),
+	}
+
+	switch {
+	case details.Reason == prefab.ReasonNoMatch:
+		// Nothing matched, so the caller's default is the answer.
+		detail.Reason = of.DefaultReason
+		detail.Variant = ""
+
+		return defaultValue, detail
+	case err != nil:
+		detail.Reason = of.ErrorReason
+		detail.ResolutionError = resolutionError(err)
+
+		return defaultValue, detail
+	case !ok:
+		detail.Reason = of.ErrorReason
+		detail.ResolutionError = of.NewTypeMismatchResolutionError(fmt.Sprintf("%s does not have a %T value", details.Key, defaultValue))
+
+		return defaultValue, detail
+	default:
+		return details.Value, detail
+	}
+}
+
+func resolutionError(err error) of.ResolutionError {
+	switch {
+	case errors.Is(err, prefab.ErrConfigDoesNotExist):
+		return of.NewFlagNotFoundResolutionError(err.Error())
+	case errors.Is(err, prefab.ErrInitializationTimeout), errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return of.NewProviderNotReadyResolutionError(err.Error())
+	default:
+		return of.NewGeneralResolutionError(err.Error())
+	}
+}
+
+func reason(evaluationReason prefab.EvaluationReason) of.Reason {
+	switch evaluationReason {
+	case prefab.ReasonTargetingMatch, prefab.ReasonSegmentMatch:
+		return of.TargetingMatchReason
+	case prefab.ReasonSplit:
+		return of.SplitReason
+	case prefab.ReasonDefault, prefab.ReasonProvided, prefab.ReasonDecrypted:
+		return of.StaticReason
+	case prefab.ReasonNoMatch, prefab.ReasonDisallowedValue:
+		return of.DefaultReason
+	case prefab.ReasonError:
+		return of.ErrorReason
+	default:
+		return of.UnknownReason
+	}
+}
+
+// variant identifies the value that was chosen by its position in the config:
+// "row.conditionalValue", plus ".weightedValue" for splits.
+func variant[T any](details prefab.EvaluationDetails[T]) string {
+	if details.RowIndex == nil || details.ConditionalValueIndex == nil {
+		return ""
+	}
+
+	variant := fmt.Sprintf("%d.%d", *details.RowIndex, *details.ConditionalValueIndex)
+	if details.WeightedValueIndex != nil {
+		variant += fmt.Sprintf(".%d", *details.WeightedValueIndex)
+	}
+
+	return variant
+}
+
+func flagMetadata[T any](details prefab.EvaluationDetails[T]) of.FlagMetadata {
+	metadata := of.FlagMetadata{"prefabReason": details.Reason.String()}
+
+	if details.ConfigID != 0 {
+		metadata["configId"] = details.ConfigID
+	}
+
+	if details.Stale {
+		metadata["stale"] = true
+	}
+
+	return metadata
+}
@@ -0,0 +1,266 @@
+package openfeature_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	integrationtestsupport "github.com/prefab-cloud/prefab-cloud-go/pkg/internal/integration_test_support"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabopenfeature "github.com/prefab-cloud/prefab-cloud-go/pkg/openfeature"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func criterion(t *testing.T, property string, values ...string) *prefabProto.Criterion {
+	t.Helper()
+
+	return &prefabProto.Criterion{
+		PropertyName: property,
+		Operator:     prefabProto.Criterion_PROP_IS_ONE_OF,
+		ValueToMatch: testutils.CreateConfigValueAndAssertOk(t, values),
+	}
+}
+
+func limitConfig(t *testing.T, id int64, proLimit int64) *prefabProto.Config {
+	t.Helper()
+
+	return &prefabProto.Config{
+		Key:        "limit",
+		Id:         id,
+		ConfigType: prefabProto.ConfigType_CONFIG,
+		Rows: []*prefabProto.ConfigRow{{Values: []*prefabProto.ConditionalValue{
+			{Criteria: []*prefabProto.Criterion{criterion(t, "user.key", "vip")}, Value: testutils.CreateConfigValueAndAssertOk(t, int64(1000))},
+			{Criteria: []*prefabProto.Criterion{criterion(t, "user.plan", "pro")}, Value: testutils.CreateConfigValueAndAssertOk(t, proLimit)},
+			{Criteria: []*prefabProto.Criterion{criterion(t, "team.name", "core")}, Value: testutils.CreateConfigValueAndAssertOk(t, int64(75))},
+			{Value: testutils.CreateConfigValueAndAssertOk(t, int64(10))},
+		}}},
+	}
+}
+
+// newTestClient starts a fake API serving configs and returns a ready client
+// using it, along with the channel that streams updates to the client.
+func newTestClient(t *testing.T, configs ...*prefabProto.Config) (*prefab.Client, chan<- *prefabProto.Configs) {
+	t.Helper()
+
+	return newTestClientWithOptions(t, configs)
+}
+
+// newTestClientWithOptions is newTestClient with opts added to the client's
+// options.
+func newTestClientWithOptions(t *testing.T, configs []*prefabProto.Config, opts ...prefab.Option) (*prefab.Client, chan<- *prefabProto.Configs) {
+	t.Helper()
+
+	server, updates := integrationtestsupport.StartFakeAPIServer(t, &prefabProto.Configs{Configs: configs})
+
+	client, err := prefab.NewClient(append([]prefab.Option{
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithAPIURLs([]string{server.URL}),
+		prefab.WithAllTelemetryDisabled(),
+	}, opts...)...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { client.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, client.WaitForReady(ctx))
+
+	return client, updates
+}
+
+func TestProvider(t *testing.T) {
+	checkout := &prefabProto.Config{
+		Key:        "checkout",
+		Id:         2,
+		ConfigType: prefabProto.ConfigType_FEATURE_FLAG,
+		Rows: []*prefabProto.ConfigRow{{Values: []*prefabProto.ConditionalValue{
+			{Criteria: []*prefabProto.Criterion{criterion(t, "user.plan", "pro")}, Value: testutils.CreateConfigValueAndAssertOk(t, true)},
+		}}},
+	}
+
+	client, updates := newTestClient(t, limitConfig(t, 1, 50), checkout)
+
+	provider := prefabopenfeature.NewProvider(client)
+	require.NoError(t, of.SetNamedProviderAndWait(t.Name(), provider))
+
+	ofClient := of.NewClient(t.Name())
+	ctx := context.Background()
+
+	t.Run("context mapping", func(t *testing.T) {
+		tests := []struct {
+			name            string
+			evalCtx         of.EvaluationContext
+			expectedValue   int64
+			expectedReason  of.Reason
+			expectedVariant string
+		}{
+			{"targeting key", of.NewEvaluationContext("vip", nil), 1000, of.TargetingMatchReason, "0.0"},
+			{"bare attribute goes to user", of.NewEvaluationContext("someone", map[string]any{"plan": "pro"}), 50, of.TargetingMatchReason, "0.1"},
+			{"dotted attribute", of.NewEvaluationContext("", map[string]any{"team.name": "core"}), 75, of.TargetingMatchReason, "0.2"},
+			{"nested map attribute", of.NewEvaluationContext("", map[string]any{"team": map[string]any{"name": "core"}}), 75, of.TargetingMatchReason, "0.2"},
+			{"default row", of.NewEvaluationContext("someone", nil), 10, of.StaticReason, "0.3"},
+		}
+
+		for _, testCase := range tests {
+			t.Run(testCase.name, func(t *testing.T) {
+				details, err := ofClient.IntValueDetails(ctx, "limit", -1, testCase.evalCtx)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedValue, details.Value)
+				assert.Equal(t, testCase.expectedReason, details.Reason)
+				assert.Equal(t, testCase.expectedVariant, details.Variant)
+				assert.Equal(t, int64(1), details.FlagMetadata["configId"])
+			})
+		}
+	})
+
+	t.Run("no match returns the default", func(t *testing.T) {
+		details, err := ofClient.BooleanValueDetails(ctx, "checkout", true, of.NewEvaluationContext("someone", nil))
+		require.NoError(t, err)
+		assert.True(t, details.Value)
+		assert.Equal(t, of.DefaultReason, details.Reason)
+
+		details, err = ofClient.BooleanValueDetails(ctx, "checkout", false, of.NewEvaluationContext("someone", map[string]any{"plan": "pro"}))
+		require.NoError(t, err)
+		assert.True(t, details.Value)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		details, err := ofClient.IntValueDetails(ctx, "does.not.exist", 3, of.EvaluationContext{})
+		require.Error(t, err)
+		assert.Equal(t, int64(3), details.Value)
+		assert.Equal(t, of.FlagNotFoundCode, details.ErrorCode)
+
+		stringDetails, err := ofClient.StringValueDetails(ctx, "limit", "fallback", of.EvaluationContext{})
+		require.Error(t, err)
+		assert.Equal(t, "fallback", stringDetails.Value)
+		assert.Equal(t, of.TypeMismatchCode, stringDetails.ErrorCode)
+	})
+
+	t.Run("config changes are emitted", func(t *testing.T) {
+		changed := make(chan of.EventDetails, 10)
+		callback := func(details of.EventDetails) { changed <- details }
+
+		ofClient.AddHandler(of.ProviderConfigChange, &callback)
+
+		timeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		greeting := &prefabProto.Config{
+			Key:        "greeting",
+			Id:         4,
+			ConfigType: prefabProto.ConfigType_CONFIG,
+			Rows:       []*prefabProto.ConfigRow{{Values: []*prefabProto.ConditionalValue{{Value: testutils.CreateConfigValueAndAssertOk(t, "hello")}}}},
+		}
+
+		select {
+		case updates <- &prefabProto.Configs{Configs: []*prefabProto.Config{limitConfig(t, 3, 60), greeting}}:
+		case <-timeout.Done():
+			t.Fatal("SSE connection was never opened")
+		}
+
+		// one event for the whole update, and none for the initial load
+		select {
+		case details := <-changed:
+			assert.ElementsMatch(t, []string{"limit", "greeting"}, details.FlagChanges)
+		case <-timeout.Done():
+			t.Fatal("timed out waiting for a config change event")
+		}
+
+		select {
+		case details := <-changed:
+			t.Fatalf("unexpected config change event: %v", details)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		value, err := ofClient.IntValue(ctx, "limit", -1, of.NewEvaluationContext("someone", map[string]any{"plan": "pro"}))
+		require.NoError(t, err)
+		assert.Equal(t, int64(60), value)
+	})
+}
+
+func TestProviderInitFailsWhenClientIsNotReady(t *testing.T) {
+	client, err := prefab.NewClient(
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithAPIURLs([]string{"http://127.0.0.1:1"}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	provider := prefabopenfeature.NewProvider(client, prefabopenfeature.WithInitTimeout(50*time.Millisecond))
+
+	require.ErrorIs(t, provider.Init(of.EvaluationContext{}), context.DeadlineExceeded)
+}
+
+func TestProviderEvaluationRespectsContext(t *testing.T) {
+	client, err := prefab.NewClient(
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithAPIURLs([]string{"http://127.0.0.1:1"}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	provider := prefabopenfeature.NewProvider(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	detail := provider.IntEvaluation(ctx, "limit", 3, of.FlattenedContext{})
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int64(3), detail.Value)
+	assert.Equal(t, of.ProviderNotReadyCode, detail.ResolutionDetail().ErrorCode)
+}
+
+func TestProviderStaleEvents(t *testing.T) {
+	client, updates := newTestClientWithOptions(t, []*prefabProto.Config{limitConfig(t, 1, 50)}, prefab.WithMaxStaleness(200*time.Millisecond))
+
+	require.NoError(t, of.SetNamedProviderAndWait(t.Name(), prefabopenfeature.NewProvider(client)))
+
+	ofClient := of.NewClient(t.Name())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stale := make(chan of.EventDetails, 10)
+	staleCallback := func(details of.EventDetails) { stale <- details }
+	ofClient.AddHandler(of.ProviderStale, &staleCallback)
+
+	// the fake API's stream stays quiet, so the configs go stale
+	select {
+	case <-stale:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for a stale event")
+	}
+
+	details, err := ofClient.IntValueDetails(ctx, "limit", -1, of.NewEvaluationContext("someone", nil))
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), details.Value)
+	assert.Equal(t, true, details.FlagMetadata["stale"])
+
+	ready := make(chan of.EventDetails, 10)
+	readyCallback := func(details of.EventDetails) { ready <- details }
+	ofClient.AddHandler(of.ProviderReady, &readyCallback)
+
+	select {
+	case updates <- &prefabProto.Configs{KeepAlive: proto.Bool(true)}:
+	case <-ctx.Done():
+		t.Fatal("SSE connection was never opened")
+	}
+
+	select {
+	case details := <-ready:
+		assert.Equal(t, "prefab configs are up to date", details.Message)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for a ready event")
+	}
+}
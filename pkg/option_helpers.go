@@ -1,9 +1,13 @@
 package prefab
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/options"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
 )
 
 // Option is a function that modifies the options for the prefab client.
@@ -45,10 +49,16 @@ func WithOfflineSources(sources []string) Option {
 
 // WithSources allows providing custom sources for the prefab client to use.
 // This prepends your sources to the default sources (API + SSE).
+//
+// Use "poll://" (or e.g. "poll://1m" for a custom interval) with
+// excludeDefault to get updates from the API by polling instead of SSE. A
+// poll:// source without excludeDefault is an error, as the client would
+// otherwise both poll and stream from the API.
 func WithSources(sources []string, excludeDefault bool) Option {
 	// some of these are their own datastore, some map to the same datastore (e.g. poll and sse)
 	return func(o *options.Options) error {
 		configSources := make([]options.ConfigSource, 0, len(sources))
+		polling := false
 
 		for _, source := range sources {
 			configSource, err := options.ParseConfigSource(source)
@@ -56,9 +66,14 @@ func WithSources(sources []string, excludeDefault bool) Option {
 				return err
 			}
 
+			polling = polling || configSource.Store == options.Poll
 			configSources = append(configSources, configSource)
 		}
 
+		if polling && !excludeDefault {
+			return errors.New("poll:// sources replace the default API source, so they need excludeDefault")
+		}
+
 		if !excludeDefault {
 			configSources = append(configSources, options.GetDefaultConfigSources()...)
 		}
@@ -98,6 +113,38 @@ func WithAPIURLs(apiURL []string) Option {
 	}
 }
 
+// WithHTTPClient sets the http.Client used for every request the prefab client
+// makes: loading configs, the SSE stream and telemetry. Use it to configure
+// proxies, custom CAs, mTLS or timeouts. The client is copied, and its Timeout
+// (30 seconds if it has none) isn't applied to the SSE stream, which stays
+// open indefinitely.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options.Options) error {
+		if client == nil {
+			return errors.New("http client must not be nil")
+		}
+
+		o.HTTPClient = client
+
+		return nil
+	}
+}
+
+// WithTransport sets the http.RoundTripper used for every request the prefab
+// client makes (replacing the Transport of any WithHTTPClient client), e.g.
+// to wrap http.DefaultTransport with middleware for metrics or tracing.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options.Options) error {
+		if transport == nil {
+			return errors.New("transport must not be nil")
+		}
+
+		o.HTTPTransport = transport
+
+		return nil
+	}
+}
+
 // WithInitializationTimeoutSeconds sets the initialization timeout for the prefab client. After this time, the client will either raise or continue depending on the OnInitializationFailure option.
 func WithInitializationTimeoutSeconds(timeoutSeconds float64) Option {
 	return func(o *options.Options) error {
@@ -120,6 +167,186 @@ func WithOnInitializationFailure(onInitializationFailure options.OnInitializatio
 	}
 }
 
+// WithOnDisallowedValue sets the behavior for the prefab client when a config
+// resolves to a value that isn't in its allowable values.
+//
+// The default behavior is to return an error (prefab.RejectDisallowedValue).
+//
+// To fall back to the config's first allowable value instead, use prefab.UseFirstAllowedValue.
+func WithOnDisallowedValue(onDisallowedValue options.OnDisallowedValue) Option {
+	return func(o *options.Options) error {
+		o.OnDisallowedValue = onDisallowedValue
+
+		return nil
+	}
+}
+
+// WithOnUnknownOperator registers a callback for criteria whose operator this
+// client doesn't support (e.g. one added to Prefab after this release). Such
+// criteria never match and are always logged as errors; the callback lets you
+// report them elsewhere too.
+func WithOnUnknownOperator(onUnknownOperator func(criterion *prefabProto.Criterion)) Option {
+	return func(o *options.Options) error {
+		o.OnUnknownOperator = onUnknownOperator
+
+		return nil
+	}
+}
+
+// WithProvidedResolver sets how values provided outside Prefab are looked up,
+// replacing the default of reading environment variables (see WithEnvLookup).
+// It is used for every provided value, including secret keys used for
+// decryption.
+//
+// Example:
+//
+//	prefab.WithProvidedResolver(prefab.ProvidedResolverChain{
+//		prefab.FileProvidedResolver{Dir: "/run/secrets"},
+//		prefab.EnvProvidedResolver{},
+//	})
+func WithProvidedResolver(resolver ProvidedResolver) Option {
+	return func(o *options.Options) error {
+		o.ProvidedResolver = resolver
+
+		return nil
+	}
+}
+
+// WithSecretKeyProvider sets where the keys for decrypting encrypted config
+// values come from. The provider is asked first; if it returns
+// ErrSecretKeyNotFound the config named by the value's decrypt_with is
+// evaluated as usual. Returning several keys lets values encrypted with either
+// an old or a new key be read while keys are rotated.
+//
+// Example:
+//
+//	prefab.WithSecretKeyProvider(prefab.EnvSecretKeyProvider{
+//		EnvVars: map[string][]string{"prefab.secrets.encryption.key": {"NEW_KEY", "OLD_KEY"}},
+//	})
+func WithSecretKeyProvider(provider SecretKeyProvider) Option {
+	return func(o *options.Options) error {
+		o.SecretKeyProvider = provider
+
+		return nil
+	}
+}
+
+// WithLastKnownGoodFile saves every config load and update from the API to
+// path, in the same ConfigDump format as dump:// sources. The default context
+// and which API key and environment the configs came from are saved beside it
+// in path + ".meta". When the client starts and path exists, it serves those
+// configs immediately and then asks the API only for changes since they were
+// saved, so a start during an API outage still has the last configs it saw.
+// A file saved for a different API key (or for an environment other than the
+// one set with WithProjectEnvID) is ignored.
+func WithLastKnownGoodFile(path string) Option {
+	return func(o *options.Options) error {
+		o.LastKnownGoodPath = path
+
+		return nil
+	}
+}
+
+// WithPollInterval sets how often poll:// sources (without an interval of
+// their own) check the API for updates, and how often the API source polls
+// once it has given up on the SSE stream. The default is 30 seconds.
+func WithPollInterval(interval time.Duration) Option {
+	return func(o *options.Options) error {
+		if interval <= 0 {
+			return fmt.Errorf("poll interval must be positive, got %s", interval)
+		}
+
+		o.PollInterval = interval
+
+		return nil
+	}
+}
+
+// WithSSEFailuresBeforePolling sets how many SSE connections in a row may
+// fail (or end without delivering configs) before the API source polls for
+// updates instead. While it polls, it tries the stream again every stream
+// retry interval (see WithStreamRetryInterval) and stops polling once the
+// stream delivers. The default is 5; 0 means never give up on the stream.
+func WithSSEFailuresBeforePolling(failures int) Option {
+	return func(o *options.Options) error {
+		o.SSEFailuresBeforePolling = failures
+
+		return nil
+	}
+}
+
+// WithStreamRetryInterval sets how long the API source waits, after giving
+// up on the SSE stream and falling back to polling, before it tries the stream
+// again. The default is 5 minutes.
+func WithStreamRetryInterval(interval time.Duration) Option {
+	return func(o *options.Options) error {
+		if interval <= 0 {
+			return fmt.Errorf("stream retry interval must be positive, got %s", interval)
+		}
+
+		o.StreamRetryInterval = interval
+
+		return nil
+	}
+}
+
+// WithMaxStaleness marks the client's configs as stale when the API hasn't
+// confirmed them (with a load, an update, a keep-alive or a poll) for longer
+// than maxStaleness. While they're stale, ConnectionStatus and evaluation
+// details report Stale, and OnStateChange listeners are told when the configs
+// become stale and when they're fresh again. By default staleness isn't
+// tracked, and configs from offline sources are never stale.
+func WithMaxStaleness(maxStaleness time.Duration) Option {
+	return func(o *options.Options) error {
+		if maxStaleness <= 0 {
+			return fmt.Errorf("max staleness must be positive, got %s", maxStaleness)
+		}
+
+		o.MaxStaleness = maxStaleness
+
+		return nil
+	}
+}
+
+// WithRefetchWhenStale makes the client load all of its configs from the API
+// again whenever they're stale (see WithMaxStaleness), rather than waiting
+// for the stream or poller to recover.
+func WithRefetchWhenStale() Option {
+	return func(o *options.Options) error {
+		o.RefetchWhenStale = true
+
+		return nil
+	}
+}
+
+// WithOnStateChange registers listener to be called whenever the client's
+// connection state changes, including while NewClient is starting up. See
+// Client.OnStateChange.
+func WithOnStateChange(listener func(ConnectionStateChange)) Option {
+	return func(o *options.Options) error {
+		o.OnStateChange = listener
+
+		return nil
+	}
+}
+
+// WithCustomOperator registers fn to evaluate criteria whose operator is
+// operator, which must be a number that isn't a built-in operator. Configs
+// (including local datafiles, e.g. `"operator": 1001`) can then use it like
+// any other operator. fn is called with the criterion and the value of its
+// property from the context.
+func WithCustomOperator(operator prefabProto.Criterion_CriterionOperator, fn CustomOperator) Option {
+	return func(o *options.Options) error {
+		if o.CustomOperators == nil {
+			o.CustomOperators = make(map[prefabProto.Criterion_CriterionOperator]func(criterion *prefabProto.Criterion, contextValue any, contextValueExists bool) bool)
+		}
+
+		o.CustomOperators[operator] = fn
+
+		return nil
+	}
+}
+
 // WithConfigs lets you provide a map of configs to the prefab client to aid in
 // testing. This is not compatible with other sources.
 //
@@ -186,10 +413,24 @@ func WithCollectEvaluationSummaries(collect bool) Option {
 	}
 }
 
+// WithCollectLoggerCounts sets whether the client should report how many
+// messages each logger logs at each level. Counts are fed by a logging
+// bridge such as SlogHandler, or by calling RecordLog.
+//
+// The default is false
+func WithCollectLoggerCounts(collect bool) Option {
+	return func(o *options.Options) error {
+		o.CollectLoggerCounts = collect
+
+		return nil
+	}
+}
+
 func WithAllTelemetryDisabled() Option {
 	return func(o *options.Options) error {
 		o.ContextTelemetryMode = options.ContextTelemetryModes.None
 		o.CollectEvaluationSummaries = false
+		o.CollectLoggerCounts = false
 		return nil
 	}
 }
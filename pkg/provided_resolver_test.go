@@ -0,0 +1,60 @@
+package prefab_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func TestProvidedResolver(t *testing.T) {
+	secrets := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(secrets, "DB_PASSWORD"), []byte("from a file\n"), 0o600))
+
+	providedValue := func(lookup string) *prefabProto.ConfigValue {
+		return &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_Provided{Provided: &prefabProto.Provided{
+			Source: prefabProto.ProvidedSource_ENV_VAR.Enum(),
+			Lookup: internal.StringPtr(lookup),
+		}}}
+	}
+
+	split := &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_WeightedValues{WeightedValues: &prefabProto.WeightedValues{
+		WeightedValues: []*prefabProto.WeightedValue{{Weight: 100, Value: providedValue("API_TOKEN")}},
+	}}}
+
+	client, _ := newTestClientWithOptions(t,
+		[]*prefabProto.Config{
+			testConfig("db.password", 1, prefabProto.ConfigType_CONFIG, always(providedValue("DB_PASSWORD"))),
+			testConfig("api.token", 2, prefabProto.ConfigType_CONFIG, always(providedValue("API_TOKEN"))),
+			testConfig("missing", 3, prefabProto.ConfigType_CONFIG, always(providedValue("MISSING"))),
+			testConfig("split.token", 4, prefabProto.ConfigType_CONFIG, always(split)),
+		},
+		prefab.WithEnvLookup(mapEnvLookup{"DB_PASSWORD": "ignored"}),
+		prefab.WithProvidedResolver(prefab.ProvidedResolverChain{
+			prefab.FileProvidedResolver{Dir: secrets},
+			prefab.MapProvidedResolver{"API_TOKEN": "from the vault"},
+		}))
+
+	value, ok, err := client.GetStringValue("db.password", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "from a file", value)
+
+	value, _, err = client.GetStringValue("api.token", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.Equal(t, "from the vault", value)
+
+	value, _, err = client.GetStringValue("split.token", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.Equal(t, "from the vault", value, "a provided value picked by a split is resolved too")
+
+	_, ok, err = client.GetStringValue("missing", prefab.ContextSet{})
+	require.ErrorIs(t, err, prefab.ErrProvidedValueNotFound)
+	assert.False(t, ok)
+}
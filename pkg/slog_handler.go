@@ -0,0 +1,306 @@
+package prefab
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/contexts"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/utils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+const (
+	// LevelTrace is the slog level used for the TRACE log level.
+	LevelTrace = slog.LevelDebug - 4
+	// LevelFatal is the slog level used for the FATAL log level.
+	LevelFatal = slog.LevelError + 4
+
+	defaultLogLevelKeyPrefix = "log-level"
+	defaultLoggerNameKey     = "logger"
+)
+
+type contextSetContextKey struct{}
+
+// ContextWithContextSet returns a copy of ctx carrying contextSet. A
+// SlogHandler evaluates log levels for records logged with the returned
+// context (e.g. via slog.InfoContext) against contextSet.
+func ContextWithContextSet(ctx context.Context, contextSet *ContextSet) context.Context {
+	return context.WithValue(ctx, contextSetContextKey{}, contextSet)
+}
+
+// ContextSetFromContext returns the ContextSet stored by ContextWithContextSet, if any.
+func ContextSetFromContext(ctx context.Context) (*ContextSet, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	contextSet, ok := ctx.Value(contextSetContextKey{}).(*ContextSet)
+
+	return contextSet, ok && contextSet != nil
+}
+
+// SlogHandler wraps a slog.Handler and filters records using LOG_LEVEL
+// configs. The level for logger "a.b.c" is read from "log-level.a.b.c",
+// falling back to "log-level.a.b", "log-level.a" and finally "log-level".
+// If none of them are set the wrapped handler decides.
+//
+// The logger name is the value of the "logger" attribute, usually set with
+// logger.With("logger", "a.b.c"). Levels are re-evaluated as configs change.
+//
+// A SlogHandler can be the default handler even though evaluating a level may
+// log: records logged by the goroutine that is resolving a level defer to the
+// wrapped handler (unless their level is cached) instead of resolving again.
+type SlogHandler struct {
+	next       slog.Handler
+	levels     *logLevelLookup
+	loggerName string
+}
+
+// SlogHandlerOption configures a SlogHandler.
+type SlogHandlerOption func(*logLevelLookup)
+
+// WithLogLevelKeyPrefix sets the prefix of the configs log levels are read
+// from. Defaults to "log-level".
+func WithLogLevelKeyPrefix(prefix string) SlogHandlerOption {
+	return func(l *logLevelLookup) {
+		l.keyPrefix = prefix
+	}
+}
+
+// WithLoggerNameKey sets the attribute the logger name is read from. Defaults to "logger".
+func WithLoggerNameKey(key string) SlogHandlerOption {
+	return func(l *logLevelLookup) {
+		l.loggerNameKey = key
+	}
+}
+
+// NewSlogHandler returns a SlogHandler that sends records enabled by client's
+// log level configs to next.
+func NewSlogHandler(client *Client, next slog.Handler, opts ...SlogHandlerOption) *SlogHandler {
+	levels := &logLevelLookup{
+		client:        client,
+		keyPrefix:     defaultLogLevelKeyPrefix,
+		loggerNameKey: defaultLoggerNameKey,
+		cache:         make(map[string]cachedLevel),
+	}
+
+	for _, opt := range opts {
+		opt(levels)
+	}
+
+	levels.unsubscribe = client.changeNotifier.SubscribeBatch(func([]ChangeEvent) {
+		levels.clearCache()
+	})
+
+	return &SlogHandler{next: next, levels: levels}
+}
+
+// Close stops the handler, and every handler derived from it with WithAttrs
+// or WithGroup, from following config changes. Levels already cached are kept.
+func (h *SlogHandler) Close() {
+	h.levels.unsubscribe()
+}
+
+// Enabled reports whether a record at level would be logged.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	configuredLevel, found := h.levels.level(ctx, h.loggerName)
+	if !found {
+		return h.next.Enabled(ctx, level)
+	}
+
+	return level >= configuredLevel
+}
+
+// Handle counts record for logger telemetry and passes it on to the wrapped handler.
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.levels.client.RecordLog(h.loggerName, protoLogLevel(record.Level))
+
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler with attrs added. A logger name attribute
+// changes the name levels are looked up for.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	loggerName := h.loggerName
+
+	for _, attr := range attrs {
+		if attr.Key == h.levels.loggerNameKey {
+			loggerName = attr.Value.String()
+		}
+	}
+
+	return &SlogHandler{next: h.next.WithAttrs(attrs), levels: h.levels, loggerName: loggerName}
+}
+
+// WithGroup returns a handler that nests later attributes in the named group.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{next: h.next.WithGroup(name), levels: h.levels, loggerName: h.loggerName}
+}
+
+type cachedLevel struct {
+	level slog.Level
+	found bool
+}
+
+// logLevelLookup resolves log levels and is shared by a SlogHandler and the
+// handlers derived from it. Levels for records without a ContextSet are cached
+// until the next config change.
+type logLevelLookup struct {
+	client        *Client
+	cache         map[string]cachedLevel
+	unsubscribe   func()
+	keyPrefix     string
+	loggerNameKey string
+	generation    uint64
+	mutex         sync.RWMutex
+	// resolving holds the IDs of the goroutines resolving a level, so
+	// records they log while evaluating it don't evaluate it again
+	resolving sync.Map
+}
+
+func (l *logLevelLookup) level(ctx context.Context, loggerName string) (slog.Level, bool) {
+	if contextSet, ok := ContextSetFromContext(ctx); ok {
+		goroutine := goroutineID()
+		if _, resolving := l.resolving.Load(goroutine); resolving {
+			return 0, false
+		}
+
+		return l.resolve(goroutine, loggerName, contextSet)
+	}
+
+	l.mutex.RLock()
+	cached, ok := l.cache[loggerName]
+	generation := l.generation
+	l.mutex.RUnlock()
+
+	if ok {
+		return cached.level, cached.found
+	}
+
+	goroutine := goroutineID()
+	if _, resolving := l.resolving.Load(goroutine); resolving {
+		return 0, false
+	}
+
+	level, found := l.resolve(goroutine, loggerName, contexts.NewContextSet())
+
+	l.mutex.Lock()
+	// don't cache a level resolved from configs that changed in the meantime
+	if generation == l.generation {
+		l.cache[loggerName] = cachedLevel{level: level, found: found}
+	}
+	l.mutex.Unlock()
+
+	return level, found
+}
+
+func (l *logLevelLookup) clearCache() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.cache = make(map[string]cachedLevel)
+	l.generation++
+}
+
+// resolve walks from the most to the least specific key and returns the
+// first level that is set. goroutine is the ID of the calling goroutine.
+func (l *logLevelLookup) resolve(goroutine uint64, loggerName string, contextSet *ContextSet) (slog.Level, bool) {
+	l.resolving.Store(goroutine, struct{}{})
+	defer l.resolving.Delete(goroutine)
+
+	mergedContextSet := contexts.Merge(l.client.boundClient.context, contextSet)
+
+	for _, key := range logLevelKeys(l.keyPrefix, loggerName) {
+		match, err := l.client.configResolver.ResolveValue(key, mergedContextSet)
+		if err != nil || match.Match == nil {
+			continue
+		}
+
+		logLevel, ok := utils.ExtractLogLevelValue(match.Match)
+		if !ok {
+			continue
+		}
+
+		if level, ok := slogLevel(logLevel); ok {
+			return level, true
+		}
+	}
+
+	return 0, false
+}
+
+// goroutineID returns the ID of the calling goroutine, read from the header
+// of its stack trace ("goroutine 123 [running]:").
+func goroutineID() uint64 {
+	var buf [64]byte
+
+	stack := buf[:runtime.Stack(buf[:], false)]
+	stack = bytes.TrimPrefix(stack, []byte("goroutine "))
+
+	if index := bytes.IndexByte(stack, ' '); index >= 0 {
+		stack = stack[:index]
+	}
+
+	id, _ := strconv.ParseUint(string(stack), 10, 64)
+
+	return id
+}
+
+// logLevelKeys returns prefix.a.b.c, prefix.a.b, prefix.a and prefix for "a.b.c".
+func logLevelKeys(prefix, loggerName string) []string {
+	keys := []string{}
+
+	for name := loggerName; name != ""; {
+		keys = append(keys, prefix+"."+name)
+
+		index := strings.LastIndex(name, ".")
+		if index < 0 {
+			break
+		}
+
+		name = name[:index]
+	}
+
+	return append(keys, prefix)
+}
+
+func slogLevel(logLevel prefabProto.LogLevel) (slog.Level, bool) {
+	switch logLevel {
+	case prefabProto.LogLevel_TRACE:
+		return LevelTrace, true
+	case prefabProto.LogLevel_DEBUG:
+		return slog.LevelDebug, true
+	case prefabProto.LogLevel_INFO:
+		return slog.LevelInfo, true
+	case prefabProto.LogLevel_WARN:
+		return slog.LevelWarn, true
+	case prefabProto.LogLevel_ERROR:
+		return slog.LevelError, true
+	case prefabProto.LogLevel_FATAL:
+		return LevelFatal, true
+	default:
+		return 0, false
+	}
+}
+
+func protoLogLevel(level slog.Level) prefabProto.LogLevel {
+	switch {
+	case level < slog.LevelDebug:
+		return prefabProto.LogLevel_TRACE
+	case level < slog.LevelInfo:
+		return prefabProto.LogLevel_DEBUG
+	case level < slog.LevelWarn:
+		return prefabProto.LogLevel_INFO
+	case level < slog.LevelError:
+		return prefabProto.LogLevel_WARN
+	case level < LevelFatal:
+		return prefabProto.LogLevel_ERROR
+	default:
+		return prefabProto.LogLevel_FATAL
+	}
+}
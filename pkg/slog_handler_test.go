@@ -0,0 +1,175 @@
+package prefab_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/testutils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+func logLevelValue(level prefabProto.LogLevel) *prefabProto.ConfigValue {
+	return &prefabProto.ConfigValue{Type: &prefabProto.ConfigValue_LogLevel{LogLevel: level}}
+}
+
+func TestSlogHandler(t *testing.T) {
+	isVIP := &prefabProto.Criterion{
+		PropertyName: "user.key",
+		Operator:     prefabProto.Criterion_PROP_IS_ONE_OF,
+		ValueToMatch: testutils.CreateConfigValueAndAssertOk(t, []string{"vip"}),
+	}
+
+	client, updates := newTestClient(t,
+		testConfig("log-level", 1, prefabProto.ConfigType_LOG_LEVEL, always(logLevelValue(prefabProto.LogLevel_WARN))),
+		testConfig("log-level.app.db", 2, prefabProto.ConfigType_LOG_LEVEL, always(logLevelValue(prefabProto.LogLevel_DEBUG))),
+		testConfig("log-level.app.api", 3, prefabProto.ConfigType_LOG_LEVEL,
+			&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{isVIP}, Value: logLevelValue(prefabProto.LogLevel_TRACE)}))
+
+	var output bytes.Buffer
+
+	handler := prefab.NewSlogHandler(client, slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	logger := slog.New(handler)
+
+	enabled := func(ctx context.Context, logger *slog.Logger, level slog.Level) bool {
+		return logger.Handler().Enabled(ctx, level)
+	}
+
+	background := context.Background()
+	root := logger
+	db := logger.With("logger", "app.db.pool")
+	api := logger.With("logger", "app.api")
+	vip := prefab.ContextWithContextSet(background, prefab.NewContextSet().WithNamedContextValues("user", map[string]interface{}{"key": "vip"}))
+
+	assert.False(t, enabled(background, root, slog.LevelInfo))
+	assert.True(t, enabled(background, root, slog.LevelWarn))
+	assert.True(t, enabled(background, db, slog.LevelDebug), "inherits from log-level.app.db")
+	assert.False(t, enabled(background, db, prefab.LevelTrace))
+	assert.False(t, enabled(background, api, slog.LevelDebug), "falls back to log-level when no rule matches")
+	assert.True(t, enabled(vip, api, prefab.LevelTrace), "uses the context set from ctx")
+
+	db.Debug("connected")
+	assert.Contains(t, output.String(), "connected")
+
+	sendUpdate(t, updates, &prefabProto.Configs{Configs: []*prefabProto.Config{
+		testConfig("log-level", 4, prefabProto.ConfigType_LOG_LEVEL, always(logLevelValue(prefabProto.LogLevel_ERROR))),
+	}})
+
+	assert.Eventually(t, func() bool {
+		return !enabled(background, root, slog.LevelWarn)
+	}, 5*time.Second, 10*time.Millisecond, "picks up the new root level")
+	assert.True(t, enabled(background, db, slog.LevelDebug))
+}
+
+func TestSlogHandlerWithoutLogLevels(t *testing.T) {
+	client, err := prefab.NewClient(
+		prefab.WithConfigs(map[string]interface{}{"other": "value"}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	handler := prefab.NewSlogHandler(client, slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	assert.False(t, handler.Enabled(context.Background(), slog.LevelInfo), "defers to the wrapped handler")
+	assert.True(t, handler.Enabled(context.Background(), slog.LevelWarn))
+}
+
+func TestSlogHandlerAsDefaultHandler(t *testing.T) {
+	// evaluating log-level logs an error about the unknown operator
+	unknownOperator := &prefabProto.Criterion{
+		PropertyName: "user.key",
+		Operator:     prefabProto.Criterion_CriterionOperator(999),
+		ValueToMatch: testutils.CreateConfigValueAndAssertOk(t, []string{"vip"}),
+	}
+
+	client, _ := newTestClient(t,
+		testConfig("log-level", 1, prefabProto.ConfigType_LOG_LEVEL,
+			&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{unknownOperator}, Value: logLevelValue(prefabProto.LogLevel_DEBUG)},
+			always(logLevelValue(prefabProto.LogLevel_WARN))))
+
+	var output bytes.Buffer
+
+	handler := prefab.NewSlogHandler(client, slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	defer handler.Close()
+
+	previous := slog.Default()
+	slog.SetDefault(slog.New(handler))
+
+	defer slog.SetDefault(previous)
+
+	assert.False(t, handler.Enabled(context.Background(), slog.LevelInfo))
+	assert.True(t, handler.Enabled(context.Background(), slog.LevelWarn))
+	assert.Contains(t, output.String(), "unknown criterion operator")
+}
+
+func TestSlogHandlerAppliesLevelsWhileAnotherGoroutineResolves(t *testing.T) {
+	const slowOperator = prefabProto.Criterion_CriterionOperator(1001)
+
+	isSlow := &prefabProto.Criterion{PropertyName: "user.key", Operator: slowOperator}
+
+	resolving := make(chan struct{})
+	release := make(chan struct{})
+
+	client, _ := newTestClientWithOptions(t,
+		[]*prefabProto.Config{
+			testConfig("log-level", 1, prefabProto.ConfigType_LOG_LEVEL,
+				&prefabProto.ConditionalValue{Criteria: []*prefabProto.Criterion{isSlow}, Value: logLevelValue(prefabProto.LogLevel_DEBUG)},
+				always(logLevelValue(prefabProto.LogLevel_WARN))),
+		},
+		prefab.WithCustomOperator(slowOperator, func(_ *prefabProto.Criterion, contextValue any, _ bool) bool {
+			if contextValue == "slow" {
+				close(resolving)
+				<-release
+			}
+
+			return false
+		}))
+
+	handler := prefab.NewSlogHandler(client, slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	defer handler.Close()
+
+	slow := prefab.ContextWithContextSet(context.Background(), prefab.NewContextSet().WithNamedContextValues("user", map[string]interface{}{"key": "slow"}))
+	done := make(chan bool)
+
+	go func() {
+		done <- handler.Enabled(slow, slog.LevelInfo)
+	}()
+
+	select {
+	case <-resolving:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the level was never resolved")
+	}
+
+	other := slog.New(handler).With("logger", "other").Handler()
+	assert.False(t, other.Enabled(context.Background(), slog.LevelInfo), "log-level applies while another goroutine resolves")
+
+	close(release)
+	assert.False(t, <-done)
+}
+
+func TestSlogHandlerClose(t *testing.T) {
+	client, updates := newTestClient(t,
+		testConfig("log-level", 1, prefabProto.ConfigType_LOG_LEVEL, always(logLevelValue(prefabProto.LogLevel_WARN))))
+
+	handler := prefab.NewSlogHandler(client, slog.NewTextHandler(&bytes.Buffer{}, nil))
+	assert.True(t, handler.Enabled(context.Background(), slog.LevelWarn))
+
+	handler.Close()
+
+	sendUpdate(t, updates, &prefabProto.Configs{Configs: []*prefabProto.Config{
+		testConfig("log-level", 2, prefabProto.ConfigType_LOG_LEVEL, always(logLevelValue(prefabProto.LogLevel_ERROR))),
+	}})
+
+	assert.Eventually(t, func() bool {
+		value, _, _ := client.GetLogLevelStringValue("log-level", prefab.ContextSet{})
+
+		return value == "ERROR"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, handler.Enabled(context.Background(), slog.LevelWarn), "keeps the cached level after Close")
+}
@@ -0,0 +1,97 @@
+package prefab_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+	integrationtestsupport "github.com/prefab-cloud/prefab-cloud-go/pkg/internal/integration_test_support"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	pointer := &prefabProto.ConfigServicePointer{ProjectEnvId: 101}
+
+	// telemetry goes to the fake API too, so this test needs the server itself
+	server, updates := integrationtestsupport.StartFakeAPIServer(t, &prefabProto.Configs{
+		Configs:              []*prefabProto.Config{stringConfig(t, "foo", 1, "one")},
+		ConfigServicePointer: pointer,
+	})
+
+	var (
+		mutex sync.Mutex
+		paths []string
+	)
+
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		paths = append(paths, req.URL.Path)
+		mutex.Unlock()
+
+		// middleware can answer requests itself, too
+		if req.URL.Path == "/api/v1/telemetry" {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+		}
+
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	client, err := prefab.NewClient(
+		prefab.WithAPIKey("does-not-matter"),
+		prefab.WithAPIURLs([]string{server.URL}),
+		prefab.WithTelemetryHost(server.URL),
+		prefab.WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}),
+		prefab.WithTransport(transport))
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, client.WaitForReady(ctx))
+
+	_, _, err = client.GetStringValue("foo", prefab.ContextSet{})
+	require.NoError(t, err)
+
+	// outlast the client's timeout before using the stream
+	time.Sleep(200 * time.Millisecond)
+
+	sendUpdate(t, updates, &prefabProto.Configs{Configs: []*prefabProto.Config{stringConfig(t, "foo", 2, "two")}, ConfigServicePointer: pointer})
+
+	assert.Eventually(t, func() bool {
+		value, _, _ := client.GetStringValue("foo", prefab.ContextSet{})
+
+		return value == "two"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, client.SendTelemetry(true))
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	assert.Contains(t, paths, "/api/v1/configs/0")
+	assert.Contains(t, paths, "/api/v1/telemetry")
+
+	streams := 0
+
+	for _, path := range paths {
+		if path == "/api/v1/sse/config" {
+			streams++
+		}
+	}
+
+	assert.Equal(t, 1, streams, "the stream isn't cut off by the client's timeout")
+}
@@ -0,0 +1,276 @@
+package prefab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/prefab-cloud/prefab-cloud-go/pkg/internal/utils"
+	prefabProto "github.com/prefab-cloud/prefab-cloud-go/proto"
+)
+
+// ErrTypeMismatch is matched (via errors.Is) by the *TypeMismatchError
+// returned when a config's value can't be converted to the requested type.
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// TypeMismatchError reports that the value of Key couldn't be converted to
+// Requested. Actual describes the config's value type.
+type TypeMismatchError struct {
+	Requested reflect.Type
+	Err       error
+	Key       string
+	Actual    string
+}
+
+func (e *TypeMismatchError) Error() string {
+	message := fmt.Sprintf("config %q has a %s value which cannot be converted to %s", e.Key, e.Actual, e.Requested)
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+
+	return message
+}
+
+func (e *TypeMismatchError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+func (e *TypeMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// Get returns the value of key converted to T.
+//
+// JSON configs are decoded into T with encoding/json, so T can be a struct,
+// map or slice. Other values are returned as T when they are assignable or a
+// lossless conversion exists (e.g. an int config into an int32, or a string
+// config into a named string type). Anything else returns a *TypeMismatchError.
+func Get[T any](client *Client, key string, contextSet ContextSet) (value T, ok bool, err error) {
+	return GetCtx[T](context.Background(), client, key, contextSet)
+}
+
+// GetCtx is like Get but stops waiting for initialization when ctx is done.
+func GetCtx[T any](ctx context.Context, client *Client, key string, contextSet ContextSet) (value T, ok bool, err error) {
+	var conversionErr error
+
+	value, ok, err = clientInternalGetValueFunc(ctx, client.boundClient, key, contextSet, func(cv *prefabProto.ConfigValue) (T, bool) {
+		converted, convertErr := convertConfigValue[T](key, cv)
+		if convertErr != nil {
+			conversionErr = convertErr
+
+			return converted, false
+		}
+
+		return converted, true
+	})
+	if err != nil {
+		return value, false, err
+	}
+
+	if conversionErr != nil {
+		return value, false, conversionErr
+	}
+
+	return value, ok, nil
+}
+
+// GetWithDefault returns the value of key converted to T, or defaultValue if
+// the key doesn't exist, can't be evaluated or can't be converted.
+func GetWithDefault[T any](client *Client, key string, contextSet ContextSet, defaultValue T) (value T, wasFound bool) {
+	value, ok, err := Get[T](client, key, contextSet)
+	if err != nil || !ok {
+		return defaultValue, false
+	}
+
+	return value, true
+}
+
+// GetEnum is like Get but also returns the config's allowable values converted
+// to T. allowed is empty when the config doesn't restrict its values.
+func GetEnum[T any](client *Client, key string, contextSet ContextSet) (value T, allowed []T, ok bool, err error) {
+	value, ok, err = Get[T](client, key, contextSet)
+	if err != nil {
+		return value, nil, false, err
+	}
+
+	allowed, err = AllowableValues[T](client, key)
+	if err != nil {
+		return value, nil, false, err
+	}
+
+	return value, allowed, ok, nil
+}
+
+// AllowableValues returns the values key is allowed to resolve to, converted
+// to T. The result is empty when the config doesn't restrict its values.
+func AllowableValues[T any](client *Client, key string) ([]T, error) {
+	if err := client.awaitEvaluable(context.Background()); err != nil {
+		return nil, err
+	}
+
+	configValues, err := client.configResolver.AllowableValues(key)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]T, 0, len(configValues))
+
+	for _, configValue := range configValues {
+		converted, err := convertConfigValue[T](key, configValue)
+		if err != nil {
+			return nil, err
+		}
+
+		allowed = append(allowed, converted)
+	}
+
+	return allowed, nil
+}
+
+func convertConfigValue[T any](key string, cv *prefabProto.ConfigValue) (T, error) {
+	var zeroValue T
+
+	target := reflect.TypeOf(&zeroValue).Elem()
+
+	if jsonValue, isJSON := cv.GetType().(*prefabProto.ConfigValue_Json); isJSON {
+		if target.Kind() == reflect.Interface {
+			extracted, _, err := utils.ExtractJSONValue(cv)
+			if err != nil {
+				return zeroValue, &TypeMismatchError{Key: key, Actual: "json", Requested: target, Err: err}
+			}
+
+			if typed, ok := extracted.(T); ok {
+				return typed, nil
+			}
+
+			return zeroValue, &TypeMismatchError{Key: key, Actual: "json", Requested: target}
+		}
+
+		var decoded T
+
+		if err := json.Unmarshal([]byte(jsonValue.Json.GetJson()), &decoded); err != nil {
+			return zeroValue, &TypeMismatchError{Key: key, Actual: "json", Requested: target, Err: err}
+		}
+
+		return decoded, nil
+	}
+
+	extracted, _, err := utils.ExtractValue(cv)
+	if err != nil {
+		return zeroValue, err
+	}
+
+	actual := configValueTypeName(cv)
+
+	if typed, ok := extracted.(T); ok {
+		return typed, nil
+	}
+
+	if extracted == nil {
+		return zeroValue, &TypeMismatchError{Key: key, Actual: actual, Requested: target}
+	}
+
+	source := reflect.ValueOf(extracted)
+
+	converted, ok := convertReflectValue(source, target)
+	if !ok {
+		return zeroValue, &TypeMismatchError{Key: key, Actual: actual, Requested: target}
+	}
+
+	typed, ok := converted.Interface().(T)
+	if !ok {
+		return zeroValue, &TypeMismatchError{Key: key, Actual: actual, Requested: target}
+	}
+
+	return typed, nil
+}
+
+// convertReflectValue converts source to target without losing information:
+// numbers must fit the target type and other kinds must match exactly
+// (allowing for named types like `type Color string`).
+func convertReflectValue(source reflect.Value, target reflect.Type) (reflect.Value, bool) {
+	switch {
+	case isIntKind(source.Kind()) && isIntKind(target.Kind()):
+		converted := reflect.New(target).Elem()
+		if converted.OverflowInt(source.Int()) {
+			return reflect.Value{}, false
+		}
+
+		converted.SetInt(source.Int())
+
+		return converted, true
+	case isIntKind(source.Kind()) && isUintKind(target.Kind()):
+		converted := reflect.New(target).Elem()
+		if source.Int() < 0 || converted.OverflowUint(uint64(source.Int())) {
+			return reflect.Value{}, false
+		}
+
+		converted.SetUint(uint64(source.Int()))
+
+		return converted, true
+	case isIntKind(source.Kind()) && isFloatKind(target.Kind()):
+		// ints above 2^53 (2^24 for float32) don't all have an exact float
+		converted := source.Convert(target)
+		if float := converted.Float(); float >= math.MaxInt64 || float < math.MinInt64 || int64(float) != source.Int() {
+			return reflect.Value{}, false
+		}
+
+		return converted, true
+	case isFloatKind(source.Kind()) && isFloatKind(target.Kind()):
+		return source.Convert(target), true
+	case source.Kind() == target.Kind() && source.Type().ConvertibleTo(target):
+		return source.Convert(target), true
+	default:
+		return reflect.Value{}, false
+	}
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func configValueTypeName(cv *prefabProto.ConfigValue) string {
+	switch cv.GetType().(type) {
+	case *prefabProto.ConfigValue_Int:
+		return "int"
+	case *prefabProto.ConfigValue_String_:
+		return "string"
+	case *prefabProto.ConfigValue_Bytes:
+		return "bytes"
+	case *prefabProto.ConfigValue_Double:
+		return "double"
+	case *prefabProto.ConfigValue_Bool:
+		return "bool"
+	case *prefabProto.ConfigValue_LogLevel:
+		return "log level"
+	case *prefabProto.ConfigValue_StringList:
+		return "string list"
+	case *prefabProto.ConfigValue_Duration:
+		return "duration"
+	case *prefabProto.ConfigValue_Json:
+		return "json"
+	default:
+		return fmt.Sprintf("%T", cv.GetType())
+	}
+}
@@ -0,0 +1,122 @@
+package prefab_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	prefab "github.com/prefab-cloud/prefab-cloud-go/pkg"
+)
+
+type poolSettings struct {
+	Hosts   []string `json:"hosts"`
+	MaxSize int      `json:"maxSize"`
+}
+
+type color string
+
+func TestGet(t *testing.T) {
+	client, err := prefab.NewClient(
+		prefab.WithConfigs(map[string]interface{}{
+			"pool":     map[string]interface{}{"hosts": []string{"a", "b"}, "maxSize": 10},
+			"int.key":  int64(42),
+			"big.int":  int64(1) << 40,
+			"huge.int": int64(1)<<53 + 1,
+			"color":    "blue",
+			"float":    2.5,
+			"timeout":  5 * time.Second,
+			"bool.key": true,
+		}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	settings, ok, err := prefab.Get[poolSettings](client, "pool", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, poolSettings{Hosts: []string{"a", "b"}, MaxSize: 10}, settings)
+
+	asMap, ok, err := prefab.Get[map[string]any](client, "pool", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []any{"a", "b"}, asMap["hosts"])
+
+	i32, ok, err := prefab.Get[int32](client, "int.key", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int32(42), i32)
+
+	u, ok, err := prefab.Get[uint](client, "int.key", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint(42), u)
+
+	f, ok, err := prefab.Get[float64](client, "int.key", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.InDelta(t, 42.0, f, 0.0001)
+
+	c, ok, err := prefab.Get[color](client, "color", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, color("blue"), c)
+
+	d, ok, err := prefab.Get[time.Duration](client, "timeout", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, d)
+
+	_, ok, err = prefab.Get[int32](client, "big.int", prefab.ContextSet{})
+	require.ErrorIs(t, err, prefab.ErrTypeMismatch)
+	assert.False(t, ok)
+
+	_, ok, err = prefab.Get[float64](client, "huge.int", prefab.ContextSet{})
+	require.ErrorIs(t, err, prefab.ErrTypeMismatch)
+	assert.False(t, ok)
+
+	f32, ok, err := prefab.Get[float32](client, "big.int", prefab.ContextSet{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.InDelta(t, float32(1<<40), f32, 0.0001)
+
+	_, ok, err = prefab.Get[float32](client, "huge.int", prefab.ContextSet{})
+	require.ErrorIs(t, err, prefab.ErrTypeMismatch)
+	assert.False(t, ok)
+
+	_, ok, err = prefab.Get[int](client, "float", prefab.ContextSet{})
+	require.ErrorIs(t, err, prefab.ErrTypeMismatch)
+	assert.False(t, ok)
+
+	_, _, err = prefab.Get[poolSettings](client, "bool.key", prefab.ContextSet{})
+	require.ErrorIs(t, err, prefab.ErrTypeMismatch)
+
+	var mismatch *prefab.TypeMismatchError
+
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, "bool.key", mismatch.Key)
+	assert.Equal(t, "bool", mismatch.Actual)
+
+	_, ok, err = prefab.Get[[]int](client, "pool", prefab.ContextSet{})
+	require.ErrorIs(t, err, prefab.ErrTypeMismatch)
+	assert.False(t, ok)
+}
+
+func TestGetWithDefault(t *testing.T) {
+	client, err := prefab.NewClient(
+		prefab.WithConfigs(map[string]interface{}{"color": "blue"}),
+		prefab.WithAllTelemetryDisabled())
+	require.NoError(t, err)
+
+	c, found := prefab.GetWithDefault(client, "color", prefab.ContextSet{}, color("red"))
+	assert.True(t, found)
+	assert.Equal(t, color("blue"), c)
+
+	c, found = prefab.GetWithDefault(client, "missing", prefab.ContextSet{}, color("red"))
+	assert.False(t, found)
+	assert.Equal(t, color("red"), c)
+
+	n, found := prefab.GetWithDefault(client, "color", prefab.ContextSet{}, 7)
+	assert.False(t, found)
+	assert.Equal(t, 7, n)
+}